@@ -0,0 +1,70 @@
+package hangul_regexp
+
+import "unicode/utf8"
+
+// The fillers standing for a missing choseong or jungseong of a syllable of
+// conjoining jamo.
+const (
+	choseongFiller  = 0x115F
+	jungseongFiller = 0x1160
+)
+
+// IsChoseongJamo reports whether ch is a conjoining choseong, including the
+// archaic ones, those of Jamo Extended-A and the filler.
+func IsChoseongJamo(ch rune) bool {
+	return 0x1100 <= ch && ch <= choseongFiller || 0xA960 <= ch && ch <= 0xA97C
+}
+
+// IsJungseongJamo reports whether ch is a conjoining jungseong, including the
+// archaic ones, those of Jamo Extended-B and the filler.
+func IsJungseongJamo(ch rune) bool {
+	return jungseongFiller <= ch && ch <= 0x11A7 || 0xD7B0 <= ch && ch <= 0xD7C6
+}
+
+// IsJongseongJamo reports whether ch is a conjoining jongseong, including the
+// archaic ones and those of Jamo Extended-B.
+func IsJongseongJamo(ch rune) bool {
+	return 0x11A8 <= ch && ch <= 0x11FF || 0xD7CB <= ch && ch <= 0xD7FB
+}
+
+// IsArchaicJamo reports whether ch is a jamo no longer used in modern Korean,
+// such as 'ᅀ' (U+1140), 'ᅙ' (U+1159), 'ᆞ' (U+119E) or the compatibility jamo
+// 'ㅿ' (U+317F).
+func IsArchaicJamo(ch rune) bool {
+	if IsChoseongJamo(ch) || IsJungseongJamo(ch) || IsJongseongJamo(ch) {
+		return ConjoiningToCompat(ch) < 0 && ch != choseongFiller && ch != jungseongFiller
+	}
+	return 0x3165 <= ch && ch <= 0x318E
+}
+
+// DisassembleJamoSyllable splits the syllable of conjoining jamo at the start
+// of s into its choseong, jungseong and jongseong. A syllable is a run of
+// choseong followed by a run of jungseong and optionally a run of jongseong,
+// as archaic syllables may have several jamo of each. size is the number of
+// bytes of the syllable, or 0 if s does not start with one.
+func DisassembleJamoSyllable(s string) (choseong, jungseong, jongseong string, size int) {
+	cho := jamoRun(s, IsChoseongJamo)
+	if cho == 0 {
+		return "", "", "", 0
+	}
+	jung := cho + jamoRun(s[cho:], IsJungseongJamo)
+	if jung == cho {
+		return "", "", "", 0
+	}
+	jong := jung + jamoRun(s[jung:], IsJongseongJamo)
+	return s[:cho], s[cho:jung], s[jung:jong], jong
+}
+
+// jamoRun returns the number of bytes of the runes at the start of s that
+// satisfy f.
+func jamoRun(s string, f func(rune) bool) int {
+	n := 0
+	for n < len(s) {
+		ch, size := utf8.DecodeRuneInString(s[n:])
+		if !f(ch) {
+			break
+		}
+		n += size
+	}
+	return n
+}
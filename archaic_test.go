@@ -0,0 +1,85 @@
+package hangul_regexp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIsArchaicJamo(t *testing.T) {
+	tests := []struct {
+		ch   rune
+		want bool
+	}{
+		{'ᅀ', true},
+		{'ᅙ', true},
+		{'ᆞ', true},
+		{'ᇫ', true},
+		{'ㅿ', true},
+		{'ꥠ', true},
+		{'ힰ', true},
+		{'ퟋ', true},
+		{'ᄀ', false},
+		{'ᅡ', false},
+		{'ᆨ', false},
+		{'ᅟ', false},
+		{'ᅠ', false},
+		{'ㄱ', false},
+		{'가', false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.ch), func(t *testing.T) {
+			if got := IsArchaicJamo(tt.ch); got != tt.want {
+				t.Errorf("IsArchaicJamo() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDisassembleJamoSyllable(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want []string
+		size int
+	}{
+		{"Archaic jungseong", "ᄒᆞᆫ글", []string{"ᄒ", "ᆞ", "ᆫ"}, 9},
+		{"Archaic choseong", "ᅀᅵ", []string{"ᅀ", "ᅵ", ""}, 6},
+		{"Choseong cluster", "ᄀ가", []string{"ᄀᄀ", "ᅡ", ""}, 9},
+		{"Extended", "ꥠힰퟋ", []string{"ꥠ", "ힰ", "ퟋ"}, 9},
+		{"Modern", "\u1100\u1161\u11a8", []string{"\u1100", "\u1161", "\u11a8"}, 9},
+		{"No jungseong", "ᄀ가", []string{"", "", ""}, 0},
+		{"Jungseong", "ᅡ", []string{"", "", ""}, 0},
+		{"Syllable", "가", []string{"", "", ""}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			choseong, jungseong, jongseong, size := DisassembleJamoSyllable(tt.s)
+			if got := []string{choseong, jungseong, jongseong}; !reflect.DeepEqual(got, tt.want) || size != tt.size {
+				t.Errorf("DisassembleJamoSyllable() got = %q, %d, want %q, %d", got, size, tt.want, tt.size)
+			}
+		})
+	}
+}
+
+func TestMatcher_Archaic(t *testing.T) {
+	tests := []struct {
+		name   string
+		search string
+		target string
+		want   []int
+	}{
+		{"Syllable", "ᄒᆞᆫ", "훈민정음 ᄒᆞᆫ글", []int{13, 22}},
+		{"Not split", "ᄒᆞᆫ", "ᄒ ᆞ ᆫ", nil},
+		{"Around syllable", "ᄒᆞᆫ글", "ᄒᆞᆫ 나라 글", []int{0, 20}},
+	}
+	for _, tt := range tests {
+		for _, engine := range []Engine{EngineRegexp, EngineNative} {
+			t.Run(tt.name, func(t *testing.T) {
+				m := MustNewMatcher(tt.search, WithFuzzy(), WithEngine(engine))
+				if got := m.FindStringIndex(tt.target); !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("FindStringIndex() got = %v, want %v", got, tt.want)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package hangul_regexp
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// AssembleString composes a sequence of compatibility jamo into syllables
+// following the 2-beolsik automaton, e.g. "ㄱㅏㄴㅏ" becomes "가나". Characters
+// other than jamo are kept as is.
+func AssembleString(jamo string) string {
+	assembled, _ := assembleString(jamo)
+	return assembled
+}
+
+// assembleString returns the assembled string and, for each of its byte
+// offsets and its length, the byte offset in jamo where that part starts.
+func assembleString(jamo string) (string, []int) {
+	a := jamoAssembler{
+		cho:  -1,
+		jung: -1,
+		jong: -1,
+	}
+	a.builder.Grow(len(jamo))
+	a.sources = make([]int, 0, len(jamo)+1)
+	for i, ch := range jamo {
+		a.write(ch, i)
+	}
+	a.flush(len(jamo))
+	a.sources = append(a.sources, len(jamo))
+	return a.builder.String(), a.sources
+}
+
+type jamoAssembler struct {
+	builder strings.Builder
+	sources []int
+
+	// cho, jung and jong are the jamo of the syllable being composed, or -1.
+	cho, jung, jong rune
+	// start is the offset of the syllable being composed, and jongStart the
+	// offset of the last consonant added to its jongseong.
+	start, jongStart int
+}
+
+func (a *jamoAssembler) write(ch rune, i int) {
+	switch {
+	case CanBeChoseongOrJongseong(ch):
+		a.writeConsonant(ch, i)
+	case IsJungseong(ch):
+		a.writeVowel(ch, i)
+	default:
+		a.flush(i)
+		a.emit(ch, i)
+	}
+}
+
+func (a *jamoAssembler) writeConsonant(ch rune, i int) {
+	if a.cho >= 0 && a.jung >= 0 {
+		if a.jong < 0 {
+			if GetJongseongOffset(ch) > 0 {
+				a.jong = ch
+				a.jongStart = i
+				return
+			}
+		} else if compound := CombineJongseong(a.jong, ch); compound >= 0 {
+			a.jong = compound
+			a.jongStart = i
+			return
+		}
+	}
+	a.flush(i)
+	if CanBeChoseong(ch) {
+		a.cho = ch
+		a.start = i
+	} else {
+		a.emit(ch, i)
+	}
+}
+
+func (a *jamoAssembler) writeVowel(ch rune, i int) {
+	if a.jong >= 0 {
+		// The batchim moves over to the new syllable.
+		next := a.jong
+		if CanBeChoseong(a.jong) {
+			a.jong = -1
+		} else {
+			a.jong, next = SplitJongseong(a.jong)
+		}
+		a.flush(a.jongStart)
+		a.cho = next
+		a.jung = ch
+		a.start = a.jongStart
+		return
+	}
+	if a.jung >= 0 {
+		if combined := CombineJungseong(a.jung, ch); combined >= 0 {
+			a.jung = combined
+			return
+		}
+		a.flush(i)
+		a.jung = ch
+		a.start = i
+		return
+	}
+	if a.cho < 0 {
+		a.start = i
+	}
+	a.jung = ch
+}
+
+// flush emits the syllable being composed, which ends at offset i.
+func (a *jamoAssembler) flush(i int) {
+	switch {
+	case a.cho >= 0 && a.jung >= 0:
+		a.emit(Assemble(GetChoseongOffset(a.cho), GetJungseongOffset(a.jung), GetJongseongOffset(a.jong)), a.start)
+	case a.cho >= 0:
+		a.emit(a.cho, a.start)
+	case a.jung >= 0:
+		a.emit(a.jung, a.start)
+	}
+	a.cho, a.jung, a.jong = -1, -1, -1
+	a.start = i
+}
+
+func (a *jamoAssembler) emit(ch rune, source int) {
+	a.builder.WriteRune(ch)
+	for range utf8.RuneLen(ch) {
+		a.sources = append(a.sources, source)
+	}
+}
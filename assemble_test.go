@@ -0,0 +1,51 @@
+package hangul_regexp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAssembleString(t *testing.T) {
+	tests := []struct {
+		jamo string
+		want string
+	}{
+		{"", ""},
+		{"ㄱㅏㄴㅏ", "가나"},
+		{"ㄱㅏㄴ", "간"},
+		{"ㄱㅏㅂㅅ", "값"},
+		{"ㄱㅏㅂㅅㅏ", "갑사"},
+		{"ㄷㅏㄹㄱㅇㅡㄴ", "닭은"},
+		{"ㄱㅗㅏ", "과"},
+		{"ﾡￂﾤ", "간"},
+		{"ㄱㅏﾪㅏ", "갈가"},
+		{"ﾡ", "ﾡ"},
+		{"ㅇㅡㅣㅅㅏ", "의사"},
+		{"ㅇㅏㅋㅔㅇㅣㄴ", "아케인"},
+		{"ㄸㅏㄸㅏ", "따따"},
+		{"ㄱㄴㄷ", "ㄱㄴㄷ"},
+		{"ㅏㅗㅏ", "ㅏㅘ"},
+		{"ㄱㅏ ㄴㅏ1", "가 나1"},
+		{"ㄳㅏ", "ㄳㅏ"},
+		{"ㄱㅏㄳㅏ", "각사"},
+		{"이미 가ㄴㅏ", "이미 가나"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.jamo, func(t *testing.T) {
+			if got := AssembleString(tt.jamo); got != tt.want {
+				t.Errorf("AssembleString() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAssembleStringSources(t *testing.T) {
+	got, sources := assembleString("aㄱㅏㅂㅅㅏ")
+	if got != "a갑사" {
+		t.Fatalf("assembleString() got = %v, want %v", got, "a갑사")
+	}
+	want := []int{0, 1, 1, 1, 10, 10, 10, 16}
+	if !reflect.DeepEqual(sources, want) {
+		t.Errorf("assembleString() sources = %v, want %v", sources, want)
+	}
+}
@@ -0,0 +1,215 @@
+package hangul_regexp
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// The modern conjoining jamo, which NFD text uses for Hangul syllables.
+const (
+	conjoiningChoseongBase  = 0x1100
+	conjoiningJungseongBase = 0x1161
+	// conjoiningJongseongBase is the jongseong before U+11A8 ᆨ, matching
+	// the empty jongseong at offset 0.
+	conjoiningJongseongBase = 0x11A7
+)
+
+func IsConjoiningChoseong(ch rune) bool {
+	return conjoiningChoseongBase <= ch && ch < conjoiningChoseongBase+rune(len(choseongs))
+}
+
+func IsConjoiningJungseong(ch rune) bool {
+	return conjoiningJungseongBase <= ch && ch < conjoiningJungseongBase+rune(len(jungseongs))
+}
+
+func IsConjoiningJongseong(ch rune) bool {
+	return conjoiningJongseongBase < ch && ch < conjoiningJongseongBase+rune(len(jongseongs))
+}
+
+// ConjoiningToCompat returns the compatibility jamo of a modern conjoining
+// jamo, e.g. 'ᄀ' (U+1100) and 'ᆨ' (U+11A8) both become 'ㄱ', or -1.
+func ConjoiningToCompat(ch rune) rune {
+	switch {
+	case IsConjoiningChoseong(ch):
+		return choseongs[ch-conjoiningChoseongBase]
+	case IsConjoiningJungseong(ch):
+		return jungseongs[ch-conjoiningJungseongBase]
+	case IsConjoiningJongseong(ch):
+		return jongseongs[ch-conjoiningJongseongBase]
+	}
+	return -1
+}
+
+// ChoseongToConjoining returns the conjoining choseong of a compatibility
+// jamo, or -1.
+func ChoseongToConjoining(choseong rune) rune {
+	if choOffset := GetChoseongOffset(choseong); choOffset >= 0 {
+		return conjoiningChoseongBase + rune(choOffset)
+	}
+	return -1
+}
+
+// JungseongToConjoining returns the conjoining jungseong of a compatibility
+// jamo, or -1.
+func JungseongToConjoining(jungseong rune) rune {
+	if jungOffset := GetJungseongOffset(jungseong); jungOffset >= 0 {
+		return conjoiningJungseongBase + rune(jungOffset)
+	}
+	return -1
+}
+
+// JongseongToConjoining returns the conjoining jongseong of a compatibility
+// jamo, or -1.
+func JongseongToConjoining(jongseong rune) rune {
+	if jongOffset := GetJongseongOffset(jongseong); jongOffset > 0 {
+		return conjoiningJongseongBase + rune(jongOffset)
+	}
+	return -1
+}
+
+// ComposeString composes the sequences of modern conjoining jamo of NFD text
+// into syllables, e.g. "\u1100\u1161" becomes "가". Archaic syllables and
+// other characters are kept as is.
+func ComposeString(s string) string {
+	var builder strings.Builder
+	builder.Grow(len(s))
+	for i := 0; i < len(s); {
+		if syllable, n := composeAt(s, i); n > 0 {
+			if syllable > 0 {
+				builder.WriteRune(syllable)
+			} else {
+				builder.WriteString(s[i : i+n])
+			}
+			i += n
+			continue
+		}
+		ch, size := utf8.DecodeRuneInString(s[i:])
+		builder.WriteRune(ch)
+		i += size
+	}
+	return builder.String()
+}
+
+// DecomposeString replaces the syllables of s with modern conjoining jamo, as
+// in NFD. Other characters are kept as is.
+func DecomposeString(s string) string {
+	var builder strings.Builder
+	builder.Grow(len(s) * 3)
+	for _, ch := range s {
+		if !IsHangul(ch) {
+			builder.WriteRune(ch)
+			continue
+		}
+		choOffset, jungOffset, jongOffset := Disassemble(ch)
+		builder.WriteRune(conjoiningChoseongBase + rune(choOffset))
+		builder.WriteRune(conjoiningJungseongBase + rune(jungOffset))
+		if jongOffset > 0 {
+			builder.WriteRune(conjoiningJongseongBase + rune(jongOffset))
+		}
+	}
+	return builder.String()
+}
+
+// composeAt returns the syllable composed of the conjoining jamo at byte
+// offset i of s and their length, or a length of 0 if there is none. A
+// syllable with an archaic jamo or a filler has no composed form, and is
+// returned as 0 with its whole length. Of modern jamo, as in NFC, only the
+// last choseong composes with the first jungseong, and with the first
+// jongseong if there is a single jungseong.
+func composeAt(s string, i int) (rune, int) {
+	choseong, jungseong, jongseong, n := DisassembleJamoSyllable(s[i:])
+	if n == 0 {
+		return 0, 0
+	}
+	for _, ch := range s[i : i+n] {
+		if !IsConjoiningChoseong(ch) && !IsConjoiningJungseong(ch) && !IsConjoiningJongseong(ch) {
+			return 0, n
+		}
+	}
+	cho, choSize := utf8.DecodeRuneInString(choseong)
+	if choSize != len(choseong) {
+		return 0, 0
+	}
+	jung, jungSize := utf8.DecodeRuneInString(jungseong)
+	n = choSize + jungSize
+	jongOffset := 0
+	if jungSize == len(jungseong) && jongseong != "" {
+		jong, jongSize := utf8.DecodeRuneInString(jongseong)
+		jongOffset = int(jong - conjoiningJongseongBase)
+		n += jongSize
+	}
+	return Assemble(int(cho-conjoiningChoseongBase), int(jung-conjoiningJungseongBase), jongOffset), n
+}
+
+// normalizeJamo composes the conjoining jamo of s into syllables, keeping the
+// archaic syllables, and replaces the remaining modern conjoining jamo and the
+// halfwidth jamo with compatibility jamo. It returns the normalized string
+// and, for each of its byte offsets and its length, the byte offset in s where
+// that part starts. The sources are nil if s has neither conjoining nor
+// halfwidth jamo.
+func normalizeJamo(s string) (string, []int) {
+	if !hasJamoToNormalize(s) {
+		return s, nil
+	}
+	var builder strings.Builder
+	builder.Grow(len(s))
+	sources := make([]int, 0, len(s)+1)
+	write := func(ch rune, source int) {
+		builder.WriteRune(ch)
+		for range utf8.RuneLen(ch) {
+			sources = append(sources, source)
+		}
+	}
+	for i := 0; i < len(s); {
+		ch, size := utf8.DecodeRuneInString(s[i:])
+		if syllable, n := composeAt(s, i); n > 0 {
+			if syllable > 0 {
+				write(syllable, i)
+			} else {
+				for j, ch := range s[i : i+n] {
+					write(ch, i+j)
+				}
+			}
+			i += n
+			continue
+		}
+		if compat := ConjoiningToCompat(ch); compat >= 0 {
+			ch = compat
+		} else if compat := HalfwidthToCompat(ch); compat >= 0 {
+			ch = compat
+		}
+		write(ch, i)
+		i += size
+	}
+	sources = append(sources, len(s))
+	return builder.String(), sources
+}
+
+// remapMatches replaces the spans of the matches with the offsets in sources,
+// unless sources is nil.
+func remapMatches(matches []matchedAtom, sources []int) {
+	if sources == nil {
+		return
+	}
+	for i := range matches {
+		matches[i].span = Span{sources[matches[i].span.Start], sources[matches[i].span.End]}
+	}
+}
+
+// hasJamoToNormalize reports whether s may have a conjoining jamo, encoded as
+// E1 84 80 to E1 87 BF, or a halfwidth jamo, encoded as EF BE A0 to EF BF 9C.
+func hasJamoToNormalize(s string) bool {
+	for i := 0; i+1 < len(s); i++ {
+		switch s[i] {
+		case 0xE1:
+			if 0x84 <= s[i+1] && s[i+1] <= 0x87 {
+				return true
+			}
+		case 0xEF:
+			if i+2 < len(s) && (s[i+1] == 0xBE && s[i+2] >= 0xA0 || s[i+1] == 0xBF && s[i+2] <= 0x9C) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package hangul_regexp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestConjoiningToCompat(t *testing.T) {
+	tests := []struct {
+		ch   rune
+		want rune
+	}{
+		{'ᄀ', 'ㄱ'},
+		{'ᄒ', 'ㅎ'},
+		{'ᅡ', 'ㅏ'},
+		{'ᅵ', 'ㅣ'},
+		{'ᆨ', 'ㄱ'},
+		{'ᆪ', 'ㄳ'},
+		{'ᇂ', 'ㅎ'},
+		{'ᄓ', -1},
+		{'ᆧ', -1},
+		{'ㄱ', -1},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.ch), func(t *testing.T) {
+			if got := ConjoiningToCompat(tt.ch); got != tt.want {
+				t.Errorf("ConjoiningToCompat() got = %U, want %U", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompatToConjoining(t *testing.T) {
+	if got := ChoseongToConjoining('ㄲ'); got != 'ᄁ' {
+		t.Errorf("ChoseongToConjoining() got = %U, want U+1101", got)
+	}
+	if got := ChoseongToConjoining('ㄳ'); got != -1 {
+		t.Errorf("ChoseongToConjoining() got = %U, want -1", got)
+	}
+	if got := JungseongToConjoining('ㅢ'); got != 'ᅴ' {
+		t.Errorf("JungseongToConjoining() got = %U, want U+1174", got)
+	}
+	if got := JongseongToConjoining('ㄳ'); got != 'ᆪ' {
+		t.Errorf("JongseongToConjoining() got = %U, want U+11AA", got)
+	}
+	if got := JongseongToConjoining('ㄸ'); got != -1 {
+		t.Errorf("JongseongToConjoining() got = %U, want -1", got)
+	}
+}
+
+func TestComposeString(t *testing.T) {
+	tests := []struct {
+		name       string
+		composed   string
+		decomposed string
+	}{
+		{"Syllables", "강남역", "강남역"},
+		{"Mixed", "MP5 마깃안!", "MP5 마깃안!"},
+		{"Compatibility jamo", "ㄱㅏ", "ㄱㅏ"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DecomposeString(tt.composed); got != tt.decomposed {
+				t.Errorf("DecomposeString() got = %q, want %q", got, tt.decomposed)
+			}
+			if got := ComposeString(tt.decomposed); got != tt.composed {
+				t.Errorf("ComposeString() got = %q, want %q", got, tt.composed)
+			}
+		})
+	}
+	extra := []struct {
+		s    string
+		want string
+	}{
+		{"\u1100\u1100\u1161\u11a8\u11a8", "\u1100각\u11a8"},
+		{"\u1100\u1161\u1161\u11a8", "가\u1161\u11a8"},
+		{"\u1112\u119e\u11ab", "\u1112\u119e\u11ab"},
+		{"\u115f\u1161", "\u115f\u1161"},
+	}
+	for _, tt := range extra {
+		if got := ComposeString(tt.s); got != tt.want {
+			t.Errorf("ComposeString() got = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestMatcher_Conjoining(t *testing.T) {
+	nfd := DecomposeString("마력이 깃든 안대")
+	for _, engine := range []Engine{EngineRegexp, EngineNative} {
+		m := MustNewMatcher(DecomposeString("마깃안"), WithFuzzy(), WithEngine(engine))
+		if got, want := m.FindStringIndex(nfd), []int{0, 50}; !reflect.DeepEqual(got, want) {
+			t.Errorf("FindStringIndex() got = %v, want %v", got, want)
+		}
+		if got, want := m.FindStringIndex("마력이 깃든 안대"), []int{0, 20}; !reflect.DeepEqual(got, want) {
+			t.Errorf("FindStringIndex() got = %v, want %v", got, want)
+		}
+		// Each conjoining jamo of the search is a character of its own.
+		want := []CharHighlight{
+			{0, 3, []Span{{0, 6}}}, {3, 6, []Span{{0, 6}}},
+			{6, 9, []Span{{22, 31}}}, {9, 12, []Span{{22, 31}}}, {12, 15, []Span{{22, 31}}},
+			{15, 18, []Span{{41, 50}}}, {18, 21, []Span{{41, 50}}}, {21, 24, []Span{{41, 50}}},
+		}
+		if got := m.Highlight(nfd); !reflect.DeepEqual(got, want) {
+			t.Errorf("Highlight() got = %v, want %v", got, want)
+		}
+	}
+
+	m := MustNewMatcher("ᄀ", WithChoseong())
+	if !m.MatchString("가") || !m.MatchString("ㄱ") {
+		t.Errorf("MatchString() got = false, want true")
+	}
+}
+
+func TestMatcher_Halfwidth(t *testing.T) {
+	tests := []struct {
+		name   string
+		search string
+		target string
+		want   []int
+	}{
+		{"Halfwidth search", "ﾡﾤ", "강남", []int{0, 6}},
+		{"Halfwidth target", "ㄱㄴ", "xﾡﾤ", []int{1, 7}},
+		{"Both", "ﾡﾤ", "ﾡﾤ", []int{0, 6}},
+		{"Vowel", "ﾡￂ", "ﾡￂ", []int{0, 6}},
+	}
+	for _, tt := range tests {
+		for _, engine := range []Engine{EngineRegexp, EngineNative} {
+			t.Run(tt.name, func(t *testing.T) {
+				m := MustNewMatcher(tt.search, WithChoseong(), WithEngine(engine))
+				if got := m.FindStringIndex(tt.target); !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("FindStringIndex() got = %v, want %v", got, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestNormalizeJamo(t *testing.T) {
+	tests := []struct {
+		s           string
+		want        string
+		wantSources bool
+	}{
+		{"강남", "강남", false},
+		{"ｱｲ", "ｱｲ", false},
+		{"ﾡￂ", "ㄱㅏ", true},
+		{"\u1100\u1161", "가", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			got, sources := normalizeJamo(tt.s)
+			if got != tt.want || (sources != nil) != tt.wantSources {
+				t.Errorf("normalizeJamo() got = %q, %v, want %q, sources %v", got, sources, tt.want, tt.wantSources)
+			}
+		})
+	}
+}
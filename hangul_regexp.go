@@ -1,53 +1,150 @@
 package hangul_regexp
 
 import (
-	"errors"
+	"fmt"
+	"regexp"
+	"slices"
 	"strings"
+	"unicode"
 	"unicode/utf8"
 )
 
 func GetPattern(search string, ignoreSpace bool, fuzzy bool, matchChoseong bool, capturing bool) (string, error) {
-	if ignoreSpace && fuzzy {
-		return "", errors.New("ignoreSpace and fuzzy cannot be true at the same time")
-	}
+	return Pattern(search, Options{
+		IgnoreSpace:   ignoreSpace,
+		Fuzzy:         fuzzy,
+		MatchChoseong: matchChoseong,
+		Capturing:     capturing,
+	})
+}
 
-	connector := ""
-	if ignoreSpace {
-		connector = " *?"
+// Pattern returns the regular expression matching the search. The search may
+// be in NFD or have halfwidth jamo. The pattern matches the syllables of the
+// target both precomposed and as conjoining jamo, as in NFD, and the jamo of
+// the target both as compatibility and halfwidth jamo. With Fuzzy, a syllable
+// without batchim may also match the start of a decomposed one with batchim.
+func Pattern(search string, opts ...Option) (string, error) {
+	o := NewOptions(opts...)
+	if err := o.Validate(); err != nil {
+		return "", err
 	}
-	if fuzzy {
-		connector = ".*?"
+
+	pattern, _ := buildPattern(search, o)
+	return pattern, nil
+}
+
+// buildPattern returns the pattern for valid options, along with the atom of
+// each capturing group when o.Capturing is set.
+func buildPattern(search string, o Options) (string, []atom) {
+	branches := buildBranches(search, o, true)
+	w := newPatternWriter(o.connector(), o.Capturing)
+	size := 0
+	for _, br := range branches {
+		size += preCalculateBytes(br.query, len(w.connector), o.MatchChoseong, o.Capturing) + 1
 	}
+	w.builder.Grow(size)
+	w.writeAnchored(branches, o.Anchor)
+	return w.builder.String(), w.groups
+}
 
-	builder := strings.Builder{}
-	builder.Grow(preCalculateBytes(search, len(connector), matchChoseong, capturing))
+// branch is one interpretation of the search. Atom offsets of all branches
+// refer to the original search.
+type branch struct {
+	query    string
+	segments []segment
+}
 
-	for i, ch := range search {
-		if i+utf8.RuneLen(ch) == len(search) {
-			if IsHangul(ch) {
-				writeLastHangulPattern(&builder, ch, connector, capturing)
-			} else if CanBeChoseong(ch) {
-				writeChoseongPattern(&builder, ch, capturing)
-			} else if matchChoseong && CanBeChoseongOrJongseong(ch) {
-				writeCombinedChoseongPattern(&builder, ch, connector, capturing)
-			} else {
-				writeEscaped(&builder, ch, capturing)
-			}
-		} else {
-			if matchChoseong && CanBeChoseongOrJongseong(ch) {
-				if CanBeChoseong(ch) {
-					writeChoseongPattern(&builder, ch, capturing)
-				} else {
-					writeCombinedChoseongPattern(&builder, ch, connector, capturing)
-				}
-			} else {
-				writeEscaped(&builder, ch, capturing)
+// buildBranches returns the branches of the search. The last character is
+// completed as a syllable being typed only if complete is set.
+func buildBranches(search string, o Options, complete bool) []branch {
+	search, jamoSources := normalizeJamo(search)
+	branches := buildQueryBranches(search, o, complete)
+	for i := range branches {
+		br := &branches[i]
+		if o.IgnoreSpace {
+			// The spaces are removed once the segments are built, so that
+			// they still end the syllable typed before them.
+			br.segments = removeSpaceSegments(br.segments)
+		}
+		if jamoSources != nil {
+			remapSegments(br.segments, jamoSources)
+		}
+		if o.RelaxTense || o.RelaxAspirated {
+			relaxSegments(br.segments, o.RelaxTense, o.RelaxAspirated)
+		}
+		if o.FoldCaseAndWidth {
+			foldSegments(br.segments)
+		}
+	}
+	return branches
+}
+
+// removeSpaceSegments returns the segments without those matching a
+// whitespace of the search.
+func removeSpaceSegments(segments []segment) []segment {
+	kept := segments[:0]
+	for _, seg := range segments {
+		if len(seg.alts) == 1 && len(seg.alts[0]) == 1 {
+			if a := seg.alts[0][0]; len(a.lits) == 1 && len(a.ranges) == 0 && unicode.IsSpace(a.lits[0]) {
+				continue
 			}
-			builder.WriteString(connector)
 		}
+		kept = append(kept, seg)
 	}
+	return kept
+}
 
-	return builder.String(), nil
+func buildQueryBranches(search string, o Options, complete bool) []branch {
+	query := search
+	var sources []int
+	if o.RawJamo {
+		query, sources = assembleString(search)
+	}
+	branches := []branch{newBranch(query, sources, o, complete)}
+	if o.EnglishKeyboard {
+		if hangul, sources := transliterateKeys(search); hangul != query {
+			branches = append(branches, newBranch(hangul, sources, o, complete))
+		}
+	}
+	if o.KoreanKeyboard {
+		if keys, sources := hangulToKeys(search); keys != search {
+			branches = append(branches, newLatinBranch(keys, sources))
+		}
+	}
+	if o.Romanized {
+		if br, ok := newRomanizedBranch(search, complete); ok {
+			branches = append(branches, br)
+		}
+	}
+	return branches
+}
+
+func newBranch(query string, sources []int, o Options, complete bool) branch {
+	segments := buildSegments(query, o.MatchChoseong, complete)
+	if sources != nil {
+		remapSegments(segments, sources)
+	}
+	return branch{query: query, segments: segments}
+}
+
+func Compile(search string, opts ...Option) (*regexp.Regexp, error) {
+	pattern, err := Pattern(search, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("hangul_regexp: search %q: %w", search, err)
+	}
+	regex, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("hangul_regexp: search %q: %w", search, err)
+	}
+	return regex, nil
+}
+
+func MustCompile(search string, opts ...Option) *regexp.Regexp {
+	regex, err := Compile(search, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return regex
 }
 
 func preCalculateBytes(str string, connectorLength int, matchChoseong bool, capturing bool) int {
@@ -77,97 +174,627 @@ func preCalculateBytes(str string, connectorLength int, matchChoseong bool, capt
 	}
 }
 
-func writeEscaped(builder *strings.Builder, ch rune, capturing bool) {
-	if capturing {
-		builder.WriteRune('(')
+type runeRange struct {
+	lo, hi rune
+}
+
+// atom matches a single rune of the target. start and end are the byte
+// offsets of the search characters it was built from.
+type atom struct {
+	start, end int
+	lits       []rune
+	ranges     []runeRange
+	// joined is set if the atom follows the previous one of its alternative
+	// without a connector.
+	joined bool
+}
+
+// segment is a unit of the search that is written as an alternation. Atoms of
+// an alternative are joined by the connector.
+type segment struct {
+	alts [][]atom
+}
+
+func buildSegments(search string, matchChoseong bool, complete bool) []segment {
+	b := newSegmentBuilder(utf8.RuneCountInString(search))
+	lastCh, lastSize := utf8.DecodeLastRuneInString(search)
+	lastStart := len(search) - lastSize
+	if !complete {
+		lastStart = -1
 	}
-	switch ch {
-	case '.', '^', '$', '*', '+', '?', '(', ')', '[', '{', '\\', '|':
-		builder.WriteRune('\\')
+	prev := rune(-1)
+	syllableEnd := 0
+	for i, ch := range search {
+		if i < syllableEnd {
+			continue
+		}
+		// A syllable of conjoining jamo left by normalizeJamo is archaic, and
+		// is matched as a whole.
+		if IsChoseongJamo(ch) {
+			if _, _, _, n := DisassembleJamoSyllable(search[i:]); n > 0 {
+				b.addJamoSyllable(search[i:i+n], i)
+				syllableEnd = i + n
+				prev = ch
+				continue
+			}
+		}
+		end := i + utf8.RuneLen(ch)
+		if end == lastStart && IsJungseong(lastCh) && b.addTrailingJungseong(ch, lastCh, i, end, len(search)) {
+			break
+		}
+		if end == len(search) && complete {
+			if IsHangul(ch) {
+				b.writeLastHangulPattern(ch, i, end)
+			} else if CanBeChoseong(ch) {
+				b.add(b.alt(b.choseong(ch, i, end)))
+			} else if matchChoseong && CanBeChoseongOrJongseong(ch) {
+				b.writeCombinedChoseongPattern(ch, i, end)
+			} else if IsJungseong(ch) && (prev < 0 || unicode.IsSpace(prev)) {
+				b.add(b.alt(b.loneJungseong(ch, i, end)))
+			} else {
+				b.add(b.alt(b.literal(ch, i, end)))
+			}
+		} else {
+			if matchChoseong && CanBeChoseongOrJongseong(ch) {
+				if CanBeChoseong(ch) {
+					b.add(b.alt(b.choseong(ch, i, end)))
+				} else {
+					b.writeCombinedChoseongPattern(ch, i, end)
+				}
+			} else {
+				b.add(b.alt(b.literal(ch, i, end)))
+			}
+		}
+		prev = ch
 	}
-	builder.WriteRune(ch)
-	if capturing {
-		builder.WriteRune(')')
+	return b.segments
+}
+
+// newLatinBranch returns a branch matching the Latin letters of the text
+// case-insensitively.
+func newLatinBranch(text string, sources []int) branch {
+	b := newSegmentBuilder(len(text))
+	for i, ch := range text {
+		end := i + utf8.RuneLen(ch)
+		if lower := unicode.ToLower(ch); 'a' <= lower && lower <= 'z' {
+			b.add(b.alt(atom{start: i, end: end, lits: b.lits(lower, unicode.ToUpper(ch))}))
+		} else {
+			b.add(b.alt(b.literal(ch, i, end)))
+		}
+	}
+	remapSegments(b.segments, sources)
+	return branch{query: text, segments: b.segments}
+}
+
+// remapSegments replaces the offsets of the atoms with the offsets in sources.
+func remapSegments(segments []segment, sources []int) {
+	for _, seg := range segments {
+		for _, alt := range seg.alts {
+			for i := range alt {
+				alt[i].start = sources[alt[i].start]
+				alt[i].end = sources[alt[i].end]
+			}
+		}
+	}
+}
+
+// foldSegments adds the runes equal to the literals of the atoms ignoring case
+// and width.
+func foldSegments(segments []segment) {
+	for _, seg := range segments {
+		for _, alt := range seg.alts {
+			for i := range alt {
+				lits := alt[i].lits
+				for _, lit := range alt[i].lits {
+					for _, ch := range foldCaseAndWidth(lit) {
+						if !slices.Contains(lits, ch) {
+							lits = append(lits, ch)
+						}
+					}
+				}
+				alt[i].lits = lits
+			}
+		}
+	}
+}
+
+// foldCaseAndWidth returns the halfwidth and fullwidth forms of an ASCII
+// character in both cases, or nil for other runes.
+func foldCaseAndWidth(ch rune) []rune {
+	const fullwidthOffset = '！' - '!'
+	if '！' <= ch && ch <= '～' {
+		ch -= fullwidthOffset
 	}
+	if ch < '!' || '~' < ch {
+		return nil
+	}
+	folded := []rune{ch, ch + fullwidthOffset}
+	if 'a' <= ch && ch <= 'z' {
+		folded = append(folded, ch-'a'+'A', ch-'a'+'Ａ')
+	} else if 'A' <= ch && ch <= 'Z' {
+		folded = append(folded, ch-'A'+'a', ch-'A'+'ａ')
+	}
+	return folded
+}
+
+// segmentBuilder allocates the slices of the segments from shared backing
+// arrays, as building a pattern creates many small ones.
+type segmentBuilder struct {
+	runes    []rune
+	ranges   []runeRange
+	atoms    []atom
+	alts     [][]atom
+	segments []segment
+}
+
+func newSegmentBuilder(runeCount int) *segmentBuilder {
+	return &segmentBuilder{
+		runes:    make([]rune, 0, runeCount+2),
+		ranges:   make([]runeRange, 0, runeCount+2),
+		atoms:    make([]atom, 0, runeCount+2),
+		alts:     make([][]atom, 0, runeCount+2),
+		segments: make([]segment, 0, runeCount),
+	}
+}
+
+func (b *segmentBuilder) lits(chs ...rune) []rune {
+	i := len(b.runes)
+	b.runes = append(b.runes, chs...)
+	return b.runes[i:len(b.runes):len(b.runes)]
+}
+
+func (b *segmentBuilder) rangesOf(ranges ...runeRange) []runeRange {
+	i := len(b.ranges)
+	b.ranges = append(b.ranges, ranges...)
+	return b.ranges[i:len(b.ranges):len(b.ranges)]
+}
+
+func (b *segmentBuilder) alt(atoms ...atom) []atom {
+	i := len(b.atoms)
+	b.atoms = append(b.atoms, atoms...)
+	return b.atoms[i:len(b.atoms):len(b.atoms)]
+}
+
+func (b *segmentBuilder) add(alts ...[]atom) {
+	i := len(b.alts)
+	b.alts = append(b.alts, alts...)
+	b.segments = append(b.segments, segment{alts: b.alts[i:len(b.alts):len(b.alts)]})
 }
 
-func writeChoseongPattern(builder *strings.Builder, choseong rune, capturing bool) {
+func (b *segmentBuilder) literal(ch rune, start, end int) atom {
+	return atom{start: start, end: end, lits: b.lits(ch)}
+}
+
+func (b *segmentBuilder) addJamoSyllable(syllable string, start int) {
+	i := len(b.atoms)
+	for j, ch := range syllable {
+		a := b.literal(ch, start+j, start+j+utf8.RuneLen(ch))
+		a.joined = j > 0
+		b.atoms = append(b.atoms, a)
+	}
+	b.add(b.atoms[i:len(b.atoms):len(b.atoms)])
+}
+
+func (b *segmentBuilder) choseong(choseong rune, start, end int) atom {
 	choOffset := GetChoseongOffset(choseong)
-	if capturing {
-		builder.WriteRune('(')
-		builder.WriteRune(choseong)
-		builder.WriteString("|[")
-		builder.WriteRune(Assemble(choOffset, 0, 0))
-		builder.WriteRune('-')
-		builder.WriteRune(Assemble(choOffset, len(jungseongs)-1, len(jongseongs)-1))
-		builder.WriteString("])")
-	} else {
-		builder.WriteString("(?:")
-		builder.WriteRune(choseong)
-		builder.WriteString("|[")
-		builder.WriteRune(Assemble(choOffset, 0, 0))
-		builder.WriteRune('-')
-		builder.WriteRune(Assemble(choOffset, len(jungseongs)-1, len(jongseongs)-1))
-		builder.WriteString("])")
+	return atom{
+		start:  start,
+		end:    end,
+		lits:   b.lits(choseong),
+		ranges: b.rangesOf(runeRange{Assemble(choOffset, 0, 0), Assemble(choOffset, len(jungseongs)-1, len(jongseongs)-1)}),
 	}
 }
 
-func writeCombinedChoseongPattern(builder *strings.Builder, jongseong rune, connector string, capturing bool) {
+func (b *segmentBuilder) writeCombinedChoseongPattern(jongseong rune, start, end int) {
 	firstCho, secondCho := SplitJongseong(jongseong)
-	writeChoseongPattern(builder, firstCho, capturing)
-	builder.WriteString(connector)
-	writeChoseongPattern(builder, secondCho, capturing)
+	b.add(b.alt(b.choseong(firstCho, start, end), b.choseong(secondCho, start, end)))
 }
 
-func writeLastHangulPattern(builder *strings.Builder, hangul rune, connector string, capturing bool) {
+// compoundBatchim returns an atom matching the hangul and the syllables whose
+// compound batchim starts with the batchim of the hangul.
+func (b *segmentBuilder) compoundBatchim(hangul rune, start, end int) atom {
+	choOffset, jungOffset, jongOffset := Disassemble(hangul)
+	i := len(b.ranges)
+	b.ranges = append(b.ranges, runeRange{hangul, hangul})
+	for _, second := range choseongs {
+		compound := CombineJongseong(jongseongs[jongOffset], second)
+		if compound < 0 {
+			continue
+		}
+		ch := Assemble(choOffset, jungOffset, GetJongseongOffset(compound))
+		if last := &b.ranges[len(b.ranges)-1]; last.hi+1 == ch {
+			last.hi = ch
+		} else {
+			b.ranges = append(b.ranges, runeRange{ch, ch})
+		}
+	}
+	if len(b.ranges) == i+1 && b.ranges[i].hi == hangul {
+		b.ranges = b.ranges[:i]
+		return b.literal(hangul, start, end)
+	}
+	return atom{start: start, end: end, ranges: b.ranges[i:len(b.ranges):len(b.ranges)]}
+}
+
+// addTrailingJungseong adds a segment for the last two characters of the
+// search when the trailing jungseong can form a new syllable with the
+// preceding character, as the IME would once the next key is typed:
+// a batchim moves over to the new syllable ("갑ㅏ" matches "가바"), a
+// consonant becomes its choseong ("ㅂㅏ" matches "바") and a vowel forms a
+// compound vowel ("고ㅏ" matches "과"). It returns false if there is no
+// such syllable.
+func (b *segmentBuilder) addTrailingJungseong(ch, jungseong rune, start, mid, end int) bool {
+	jungOffset := GetJungseongOffset(jungseong)
+	lastJungOffset := lastCompoundJungseongOffset(jungOffset)
+	if IsHangul(ch) {
+		choOffset, prevJungOffset, jongOffset := Disassemble(ch)
+		if jongOffset == 0 {
+			combined := CombineJungseong(jungseongs[prevJungOffset], jungseong)
+			if combined < 0 {
+				return false
+			}
+			combinedOffset := GetJungseongOffset(combined)
+			b.add(
+				b.alt(b.literal(ch, start, mid), b.literal(jungseong, mid, end)),
+				b.alt(b.syllables(choOffset, combinedOffset, combinedOffset, start, end)),
+			)
+			return true
+		}
+		jongseong := jongseongs[jongOffset]
+		base := Assemble(choOffset, prevJungOffset, 0)
+		if !CanBeChoseong(jongseong) {
+			var firstJong rune
+			firstJong, jongseong = SplitJongseong(jongseong)
+			base = Assemble(choOffset, prevJungOffset, GetJongseongOffset(firstJong))
+		}
+		b.add(
+			b.alt(b.literal(ch, start, mid), b.literal(jungseong, mid, end)),
+			b.alt(b.literal(base, start, mid), b.syllables(GetChoseongOffset(jongseong), jungOffset, lastJungOffset, start, end)),
+		)
+		return true
+	}
+	if CanBeChoseong(ch) {
+		b.add(
+			b.alt(b.literal(ch, start, mid), b.literal(jungseong, mid, end)),
+			b.alt(b.syllables(GetChoseongOffset(ch), jungOffset, lastJungOffset, start, end)),
+		)
+		return true
+	}
+	return false
+}
+
+// loneJungseong returns an atom matching the jungseong, or a syllable starting
+// with it and the silent choseong ㅇ.
+func (b *segmentBuilder) loneJungseong(jungseong rune, start, end int) atom {
+	a := b.syllables(GetChoseongOffset('ㅇ'), GetJungseongOffset(jungseong), lastCompoundJungseongOffset(GetJungseongOffset(jungseong)), start, end)
+	a.lits = b.lits(jungseong)
+	return a
+}
+
+// syllables returns an atom matching the syllables with the choseong and a
+// jungseong between the offsets, with or without batchim.
+func (b *segmentBuilder) syllables(choOffset, firstJungOffset, lastJungOffset int, start, end int) atom {
+	return atom{
+		start:  start,
+		end:    end,
+		ranges: b.rangesOf(runeRange{Assemble(choOffset, firstJungOffset, 0), Assemble(choOffset, lastJungOffset, len(jongseongs)-1)}),
+	}
+}
+
+func (b *segmentBuilder) writeLastHangulPattern(hangul rune, start, end int) {
 	choOffset, jungOffset, jongOffset := Disassemble(hangul)
 	if HasBatchim(hangul) {
 		jongseong := jongseongs[jongOffset]
 		if CanBeChoseong(jongseong) {
-			builder.WriteString("(?:")
-			writeRune(builder, hangul, capturing)
-			builder.WriteRune('|')
-			writeRune(builder, Assemble(choOffset, jungOffset, 0), capturing)
-			builder.WriteString(connector)
-			writeChoseongPattern(builder, jongseong, capturing)
-			builder.WriteRune(')')
+			b.add(
+				b.alt(b.compoundBatchim(hangul, start, end)),
+				b.alt(b.literal(Assemble(choOffset, jungOffset, 0), start, end), b.choseong(jongseong, start, end)),
+			)
 		} else {
 			firstJong, secondJong := SplitJongseong(jongseong)
-			builder.WriteString("(?:")
-			writeRune(builder, hangul, capturing)
-			builder.WriteRune('|')
-			writeRune(builder, Assemble(choOffset, jungOffset, GetJongseongOffset(firstJong)), capturing)
-			builder.WriteString(connector)
-			writeChoseongPattern(builder, secondJong, capturing)
-			builder.WriteRune(')')
+			b.add(
+				b.alt(b.literal(hangul, start, end)),
+				b.alt(b.literal(Assemble(choOffset, jungOffset, GetJongseongOffset(firstJong)), start, end), b.choseong(secondJong, start, end)),
+			)
 		}
 	} else {
-		if capturing {
-			builder.WriteRune('(')
-			builder.WriteRune(hangul)
-			builder.WriteString("|[")
-			builder.WriteRune(Assemble(choOffset, jungOffset, 1))
-			builder.WriteRune('-')
-			builder.WriteRune(Assemble(choOffset, jungOffset, len(jongseongs)-1))
-			builder.WriteString("])")
+		b.add(b.alt(atom{
+			start:  start,
+			end:    end,
+			lits:   b.lits(hangul),
+			ranges: b.rangesOf(runeRange{Assemble(choOffset, jungOffset, 1), Assemble(choOffset, lastCompoundJungseongOffset(jungOffset), len(jongseongs)-1)}),
+		}))
+	}
+}
+
+type patternWriter struct {
+	builder   strings.Builder
+	connector string
+	capturing bool
+	// groups holds the atom of each capturing group, in group order.
+	groups []atom
+}
+
+func newPatternWriter(connector string, capturing bool) *patternWriter {
+	return &patternWriter{connector: connector, capturing: capturing}
+}
+
+// wordStartPattern matches the start of the target or a character that is not
+// a letter or digit, as \b does not treat Hangul as word characters.
+const wordStartPattern = `(?:^|[^\p{L}\p{Nd}])`
+
+func (w *patternWriter) writeAnchored(branches []branch, anchor Anchor) {
+	switch anchor {
+	case AnchorPrefix, AnchorFull:
+		w.builder.WriteRune('^')
+	case AnchorWordStart:
+		w.builder.WriteString(wordStartPattern)
+	}
+	w.writeBranches(branches)
+	if anchor == AnchorFull {
+		w.builder.WriteRune('$')
+	}
+}
+
+func (w *patternWriter) writeBranches(branches []branch) {
+	if len(branches) == 1 {
+		w.writeSegments(branches[0].segments)
+		return
+	}
+	w.builder.WriteString("(?:")
+	for i, br := range branches {
+		if i > 0 {
+			w.builder.WriteRune('|')
+		}
+		w.writeSegments(br.segments)
+	}
+	w.builder.WriteRune(')')
+}
+
+func (w *patternWriter) writeSegments(segments []segment) {
+	for i, seg := range segments {
+		if i > 0 {
+			w.builder.WriteString(w.connector)
+		}
+		w.writeSegment(seg)
+	}
+}
+
+func (w *patternWriter) writeSegment(seg segment) {
+	if len(seg.alts) == 1 {
+		w.writeAlt(seg.alts[0])
+		return
+	}
+	w.builder.WriteString("(?:")
+	for i, alt := range seg.alts {
+		if i > 0 {
+			w.builder.WriteRune('|')
+		}
+		w.writeAlt(alt)
+	}
+	w.builder.WriteRune(')')
+}
+
+func (w *patternWriter) writeAlt(atoms []atom) {
+	for i, a := range atoms {
+		if i > 0 && !a.joined {
+			w.builder.WriteString(w.connector)
+		}
+		w.writeAtom(a)
+	}
+}
+
+func (w *patternWriter) writeAtom(a atom) {
+	lits := withHalfwidth(a.lits)
+	conjoining := conjoiningPattern(a.lits, a.ranges)
+	if len(a.ranges) == 0 && conjoining == "" {
+		w.openGroup(a)
+		w.writeLits(lits)
+		w.closeGroup()
+		return
+	}
+	if w.capturing {
+		w.openGroup(a)
+	} else {
+		w.builder.WriteString("(?:")
+	}
+	sep := ""
+	if len(lits) > 0 {
+		w.writeLits(lits)
+		sep = "|"
+	}
+	if len(a.ranges) > 0 {
+		w.builder.WriteString(sep)
+		w.writeRanges(a.ranges)
+		sep = "|"
+	}
+	if conjoining != "" {
+		w.builder.WriteString(sep)
+		w.builder.WriteString(conjoining)
+	}
+	w.builder.WriteRune(')')
+}
+
+// withHalfwidth returns the literals followed by the halfwidth jamo of those
+// that are compatibility jamo.
+func withHalfwidth(lits []rune) []rune {
+	lits = slices.Clip(lits)
+	for _, lit := range lits {
+		if halfwidth := CompatToHalfwidth(lit); halfwidth >= 0 {
+			lits = append(lits, halfwidth)
+		}
+	}
+	return lits
+}
+
+// conjoiningPattern returns the alternatives matching the syllables of the
+// literals and ranges as modern conjoining jamo, as in NFD, or "" if there
+// are none. Choseong sharing the following jamo are written as a class.
+func conjoiningPattern(lits []rune, ranges []runeRange) string {
+	// jongs holds the jongseong offsets of the syllables of each choseong and
+	// jungseong as a bit set.
+	var jongs [len(choseongs)][len(jungseongs)]uint32
+	found := false
+	add := func(ch rune) {
+		choOffset, jungOffset, jongOffset := Disassemble(ch)
+		jongs[choOffset][jungOffset] |= 1 << jongOffset
+		found = true
+	}
+	for _, lit := range lits {
+		if IsHangul(lit) {
+			add(lit)
+		}
+	}
+	for _, r := range ranges {
+		for ch := max(r.lo, '가'); ch <= min(r.hi, '힣'); ch++ {
+			add(ch)
+		}
+	}
+	if !found {
+		return ""
+	}
+	var tails []string
+	var chos [][]rune
+	for choOffset := range jongs {
+		tail := conjoiningTail(jongs[choOffset])
+		if tail == "" {
+			continue
+		}
+		cho := conjoiningChoseongBase + rune(choOffset)
+		if i := slices.Index(tails, tail); i >= 0 {
+			chos[i] = append(chos[i], cho)
 		} else {
-			builder.WriteString("(?:")
-			builder.WriteRune(hangul)
-			builder.WriteString("|[")
-			builder.WriteRune(Assemble(choOffset, jungOffset, 1))
-			builder.WriteRune('-')
-			builder.WriteRune(Assemble(choOffset, jungOffset, len(jongseongs)-1))
-			builder.WriteString("])")
+			tails = append(tails, tail)
+			chos = append(chos, []rune{cho})
 		}
 	}
+	var builder strings.Builder
+	for i, tail := range tails {
+		if i > 0 {
+			builder.WriteRune('|')
+		}
+		writeRuneClass(&builder, chos[i])
+		builder.WriteString(tail)
+	}
+	return builder.String()
 }
 
-func writeRune(builder *strings.Builder, ch rune, capturing bool) {
-	if capturing {
-		builder.WriteRune('(')
-		builder.WriteRune(ch)
+// conjoiningTail returns the pattern of the conjoining jungseong and jongseong
+// of the syllables of a choseong, given the jongseong bit set of each
+// jungseong. Jungseong sharing the jongseong are written as a class.
+func conjoiningTail(jongs [len(jungseongs)]uint32) string {
+	var masks []uint32
+	var jungs [][]rune
+	for jungOffset, mask := range jongs {
+		if mask == 0 {
+			continue
+		}
+		jung := conjoiningJungseongBase + rune(jungOffset)
+		if i := slices.Index(masks, mask); i >= 0 {
+			jungs[i] = append(jungs[i], jung)
+		} else {
+			masks = append(masks, mask)
+			jungs = append(jungs, []rune{jung})
+		}
+	}
+	var builder strings.Builder
+	if len(masks) > 1 {
+		builder.WriteString("(?:")
+	}
+	for i, mask := range masks {
+		if i > 0 {
+			builder.WriteRune('|')
+		}
+		writeRuneClass(&builder, jungs[i])
+		var jongRunes []rune
+		for jongOffset := 1; jongOffset < len(jongseongs); jongOffset++ {
+			if mask&(1<<jongOffset) != 0 {
+				jongRunes = append(jongRunes, conjoiningJongseongBase+rune(jongOffset))
+			}
+		}
+		if len(jongRunes) > 0 {
+			writeRuneClass(&builder, jongRunes)
+			if mask&1 != 0 {
+				builder.WriteRune('?')
+			}
+		}
+	}
+	if len(masks) > 1 {
 		builder.WriteRune(')')
-	} else {
-		builder.WriteRune(ch)
 	}
+	return builder.String()
+}
+
+func (w *patternWriter) openGroup(a atom) {
+	if w.capturing {
+		w.builder.WriteRune('(')
+		w.groups = append(w.groups, a)
+	}
+}
+
+func (w *patternWriter) closeGroup() {
+	if w.capturing {
+		w.builder.WriteRune(')')
+	}
+}
+
+func (w *patternWriter) writeLits(lits []rune) {
+	if len(lits) == 1 {
+		writeEscaped(&w.builder, lits[0])
+		return
+	}
+	w.builder.WriteRune('[')
+	for _, ch := range lits {
+		writeClassEscaped(&w.builder, ch)
+	}
+	w.builder.WriteRune(']')
+}
+
+func (w *patternWriter) writeRanges(ranges []runeRange) {
+	writeRangeClass(&w.builder, ranges)
+}
+
+func writeRangeClass(builder *strings.Builder, ranges []runeRange) {
+	builder.WriteRune('[')
+	for _, r := range ranges {
+		writeClassEscaped(builder, r.lo)
+		if r.hi != r.lo {
+			builder.WriteRune('-')
+			writeClassEscaped(builder, r.hi)
+		}
+	}
+	builder.WriteRune(']')
+}
+
+// writeRuneClass writes a class of the ascending runes, or the rune if there
+// is only one.
+func writeRuneClass(builder *strings.Builder, runes []rune) {
+	if len(runes) == 1 {
+		writeEscaped(builder, runes[0])
+		return
+	}
+	var ranges []runeRange
+	for _, ch := range runes {
+		if n := len(ranges); n > 0 && ranges[n-1].hi+1 == ch {
+			ranges[n-1].hi = ch
+		} else {
+			ranges = append(ranges, runeRange{ch, ch})
+		}
+	}
+	writeRangeClass(builder, ranges)
+}
+
+func writeEscaped(builder *strings.Builder, ch rune) {
+	switch ch {
+	case '.', '^', '$', '*', '+', '?', '(', ')', '[', '{', '\\', '|':
+		builder.WriteRune('\\')
+	}
+	builder.WriteRune(ch)
+}
+
+func writeClassEscaped(builder *strings.Builder, ch rune) {
+	switch ch {
+	case '[', ']', '^', '-', '\\':
+		builder.WriteRune('\\')
+	}
+	builder.WriteRune(ch)
 }
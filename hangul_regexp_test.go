@@ -1,6 +1,7 @@
 package hangul_regexp
 
 import (
+	"errors"
 	"fmt"
 	"regexp"
 	"slices"
@@ -8,6 +9,9 @@ import (
 	"testing"
 )
 
+// spaceGap is the connector of ignoreSpace.
+const spaceGap = `[\t-\r\x{85}\p{Z}\x{200B}-\x{200D}\x{2060}\x{FEFF}]*?`
+
 func TestGetPattern(t *testing.T) {
 	type args struct {
 		search      string
@@ -23,46 +27,65 @@ func TestGetPattern(t *testing.T) {
 		wantErr bool
 	}{
 		{"Alphabet", args{"a", false, false, false, false}, "a", false},
-		{"Hangul", args{"ㄱ나다라123", false, false, false, false}, "ㄱ나다라123", false},
-		{"Mixed", args{"Zx0ㅡㅡ", false, false, false, false}, "Zx0ㅡㅡ", false},
-		{"Should escape", args{"[^가-힣]$", false, false, false, false}, "\\[\\^가-힣]\\$", false},
-
-		{"Last char is choseong", args{"ㄱ", false, false, false, false}, "(?:ㄱ|[가-깋])", false},
-		{"Last char without batchim", args{"가 나", false, false, false, false}, "가 (?:나|[낙-낳])", false},
-		{"Last char with batchim", args{"가 안", false, false, false, false}, "가 (?:안|아(?:ㄴ|[나-닣]))", false},
-		{"Last char with double batchim", args{"가 있", false, false, false, false}, "가 (?:있|이(?:ㅆ|[싸-앃]))", false},
-		{"Last char with combined batchim", args{"가 얇", false, false, false, false}, "가 (?:얇|얄(?:ㅂ|[바-빟]))", false},
-		{"Last char is combined choseong", args{"ㄻ", false, false, false, false}, "ㄻ", false},
-
-		{"Mixed / ignoreSpace=true", args{"ㅁ가a항1", true, false, false, false}, "ㅁ *?가 *?a *?항 *?1", false},
-		{"Last char with batchim / ignoreSpace=true / has space matcher between", args{"가 안", true, false, false, false}, "가 *?  *?(?:안|아 *?(?:ㄴ|[나-닣]))", false},
-
-		{"Mixed / fuzzy=true", args{"ㅁ가a항1", false, true, false, false}, "ㅁ.*?가.*?a.*?항.*?1", false},
-		{"Space / fuzzy=true / spaces are not concatenated", args{"가 s", false, true, false, false}, "가.*? .*?s", false},
-		{"Last char with batchim / fuzzy=true / has any matcher between", args{"가 안", false, true, false, false}, "가.*? .*?(?:안|아.*?(?:ㄴ|[나-닣]))", false},
-
-		{"Non-last char is choseong / choseong=true", args{"ㄱ1", false, false, true, false}, "(?:ㄱ|[가-깋])1", false},
-		{"Multiple choseong chars / choseong=true", args{"ㄱ ㄴㄷ", false, false, true, false}, "(?:ㄱ|[가-깋]) (?:ㄴ|[나-닣])(?:ㄷ|[다-딯])", false},
-		{"Mixed with choseong / choseong=true", args{"aㅎ1가ㄴ", false, false, true, false}, "a(?:ㅎ|[하-힣])1가(?:ㄴ|[나-닣])", false},
-		{"Standalone batchim char / choseong=true", args{"ㄻㅄ", false, false, true, false}, "(?:ㄹ|[라-맇])(?:ㅁ|[마-밓])(?:ㅂ|[바-빟])(?:ㅅ|[사-싷])", false},
+		{"Hangul", args{"ㄱ나다라123", false, false, false, false}, "[ㄱﾡ](?:나|\u1102\u1161)(?:다|\u1103\u1161)(?:라|\u1105\u1161)123", false},
+		{"Mixed", args{"Zx0ㅡㅡ", false, false, false, false}, "Zx0[ㅡￚ][ㅡￚ]", false},
+		{"Should escape", args{"[^가-힣]$", false, false, false, false}, "\\[\\^(?:가|\u1100\u1161)-(?:힣|\u1112\u1175\u11c2)]\\$", false},
+
+		{"Last char is choseong", args{"ㄱ", false, false, false, false}, "(?:[ㄱﾡ]|[가-깋]|\u1100[\u1161-\u1175][\u11a8-\u11c2]?)", false},
+		{"Last char without batchim", args{"가 나", false, false, false, false}, "(?:가|\u1100\u1161) (?:나|[낙-낳]|\u1102\u1161[\u11a8-\u11c2]?)", false},
+		{"Last char with compound vowel ㅗ", args{"고", false, false, false, false}, "(?:고|[곡-굏]|\u1100[\u1169-\u116c][\u11a8-\u11c2]?)", false},
+		{"Last char with compound vowel ㅜ", args{"누", false, false, false, false}, "(?:누|[눅-뉳]|\u1102[\u116e-\u1171][\u11a8-\u11c2]?)", false},
+		{"Last char with compound vowel ㅡ", args{"스", false, false, false, false}, "(?:스|[슥-싛]|\u1109[\u1173-\u1174][\u11a8-\u11c2]?)", false},
+		{"Last char with compound vowel / capturing=true", args{"고", false, false, false, true}, "(고|[곡-굏]|\u1100[\u1169-\u116c][\u11a8-\u11c2]?)", false},
+		{"Last char with batchim", args{"가 안", false, false, false, false}, "(?:가|\u1100\u1161) (?:(?:[안-않]|\u110b\u1161[\u11ab-\u11ad])|(?:아|\u110b\u1161)(?:[ㄴﾤ]|[나-닣]|\u1102[\u1161-\u1175][\u11a8-\u11c2]?))", false},
+		{"Last char with batchim ㄱ", args{"각", false, false, false, false}, "(?:(?:[각갃]|\u1100\u1161[\u11a8\u11aa])|(?:가|\u1100\u1161)(?:[ㄱﾡ]|[가-깋]|\u1100[\u1161-\u1175][\u11a8-\u11c2]?))", false},
+		{"Last char with batchim ㄹ", args{"갈", false, false, false, false}, "(?:(?:[갈-갏]|\u1100\u1161[\u11af-\u11b6])|(?:가|\u1100\u1161)(?:[ㄹﾩ]|[라-맇]|\u1105[\u1161-\u1175][\u11a8-\u11c2]?))", false},
+		{"Last char with batchim ㅂ", args{"갑", false, false, false, false}, "(?:(?:[갑-값]|\u1100\u1161[\u11b8-\u11b9])|(?:가|\u1100\u1161)(?:[ㅂﾲ]|[바-빟]|\u1107[\u1161-\u1175][\u11a8-\u11c2]?))", false},
+		{"Last char with batchim ㅇ", args{"강", false, false, false, false}, "(?:(?:강|\u1100\u1161\u11bc)|(?:가|\u1100\u1161)(?:[ㅇﾷ]|[아-잏]|\u110b[\u1161-\u1175][\u11a8-\u11c2]?))", false},
+		{"Last char with double batchim", args{"가 있", false, false, false, false}, "(?:가|\u1100\u1161) (?:(?:있|\u110b\u1175\u11bb)|(?:이|\u110b\u1175)(?:[ㅆﾶ]|[싸-앃]|\u110a[\u1161-\u1175][\u11a8-\u11c2]?))", false},
+		{"Last char with combined batchim", args{"가 얇", false, false, false, false}, "(?:가|\u1100\u1161) (?:(?:얇|\u110b\u1163\u11b2)|(?:얄|\u110b\u1163\u11af)(?:[ㅂﾲ]|[바-빟]|\u1107[\u1161-\u1175][\u11a8-\u11c2]?))", false},
+		{"Last char is combined choseong", args{"ㄻ", false, false, false, false}, "[ㄻﾫ]", false},
+		{"Trailing jungseong after batchim", args{"가갑ㅏ", false, false, false, false}, "(?:가|\u1100\u1161)(?:(?:갑|\u1100\u1161\u11b8)[ㅏￂ]|(?:가|\u1100\u1161)(?:[바-밯]|\u1107\u1161[\u11a8-\u11c2]?))", false},
+		{"Trailing jungseong after combined batchim", args{"값ㅓ", false, false, false, false}, "(?:(?:값|\u1100\u1161\u11b9)[ㅓￆ]|(?:갑|\u1100\u1161\u11b8)(?:[서-섷]|\u1109\u1165[\u11a8-\u11c2]?))", false},
+		{"Trailing jungseong with compound vowel after batchim", args{"갑ㅗ", false, false, false, false}, "(?:(?:갑|\u1100\u1161\u11b8)[ㅗￌ]|(?:가|\u1100\u1161)(?:[보-뵣]|\u1107[\u1169-\u116c][\u11a8-\u11c2]?))", false},
+		{"Trailing jungseong after choseong", args{"가ㅂㅏ", false, false, false, false}, "(?:가|\u1100\u1161)(?:[ㅂﾲ][ㅏￂ]|(?:[바-밯]|\u1107\u1161[\u11a8-\u11c2]?))", false},
+		{"Trailing jungseong forming compound vowel", args{"고ㅏ", false, false, false, false}, "(?:(?:고|\u1100\u1169)[ㅏￂ]|(?:[과-괗]|\u1100\u116a[\u11a8-\u11c2]?))", false},
+		{"Trailing jungseong not forming compound vowel", args{"가ㅓ", false, false, false, false}, "(?:가|\u1100\u1161)[ㅓￆ]", false},
+		{"Lone jungseong", args{"ㅏ", false, false, false, false}, "(?:[ㅏￂ]|[아-앟]|\u110b\u1161[\u11a8-\u11c2]?)", false},
+		{"Lone jungseong with compound vowel", args{"가 ㅜ", false, false, false, false}, "(?:가|\u1100\u1161) (?:[ㅜￓ]|[우-윟]|\u110b[\u116e-\u1171][\u11a8-\u11c2]?)", false},
+		{"Trailing jungseong after batchim / fuzzy=true, capturing=true", args{"갑ㅏ", false, true, false, true}, "(?:(갑|\u1100\u1161\u11b8).*?([ㅏￂ])|(가|\u1100\u1161).*?([바-밯]|\u1107\u1161[\u11a8-\u11c2]?))", false},
+
+		{"Mixed / ignoreSpace=true", args{"ㅁ가a항1", true, false, false, false}, "[ㅁﾱ]" + spaceGap + "(?:가|\u1100\u1161)" + spaceGap + "a" + spaceGap + "(?:항|\u1112\u1161\u11bc)" + spaceGap + "1", false},
+		{"Last char with batchim / ignoreSpace=true / ignores space in search", args{"가 안", true, false, false, false}, "(?:가|\u1100\u1161)" + spaceGap + "(?:(?:[안-않]|\u110b\u1161[\u11ab-\u11ad])|(?:아|\u110b\u1161)" + spaceGap + "(?:[ㄴﾤ]|[나-닣]|\u1102[\u1161-\u1175][\u11a8-\u11c2]?))", false},
+		{"Trailing space / ignoreSpace=true", args{"가 ", true, false, false, false}, "(?:가|\u1100\u1161)", false},
+		{"Trailing jungseong after space / ignoreSpace=true", args{"갑 ㅏ", true, false, false, false}, "(?:갑|\u1100\u1161\u11b8)" + spaceGap + "(?:[ㅏￂ]|[아-앟]|\u110b\u1161[\u11a8-\u11c2]?)", false},
+
+		{"Mixed / fuzzy=true", args{"ㅁ가a항1", false, true, false, false}, "[ㅁﾱ].*?(?:가|\u1100\u1161).*?a.*?(?:항|\u1112\u1161\u11bc).*?1", false},
+		{"Space / fuzzy=true / spaces are not concatenated", args{"가 s", false, true, false, false}, "(?:가|\u1100\u1161).*? .*?s", false},
+		{"Last char with batchim / fuzzy=true / has any matcher between", args{"가 안", false, true, false, false}, "(?:가|\u1100\u1161).*? .*?(?:(?:[안-않]|\u110b\u1161[\u11ab-\u11ad])|(?:아|\u110b\u1161).*?(?:[ㄴﾤ]|[나-닣]|\u1102[\u1161-\u1175][\u11a8-\u11c2]?))", false},
+
+		{"Non-last char is choseong / choseong=true", args{"ㄱ1", false, false, true, false}, "(?:[ㄱﾡ]|[가-깋]|\u1100[\u1161-\u1175][\u11a8-\u11c2]?)1", false},
+		{"Multiple choseong chars / choseong=true", args{"ㄱ ㄴㄷ", false, false, true, false}, "(?:[ㄱﾡ]|[가-깋]|\u1100[\u1161-\u1175][\u11a8-\u11c2]?) (?:[ㄴﾤ]|[나-닣]|\u1102[\u1161-\u1175][\u11a8-\u11c2]?)(?:[ㄷﾧ]|[다-딯]|\u1103[\u1161-\u1175][\u11a8-\u11c2]?)", false},
+		{"Mixed with choseong / choseong=true", args{"aㅎ1가ㄴ", false, false, true, false}, "a(?:[ㅎﾾ]|[하-힣]|\u1112[\u1161-\u1175][\u11a8-\u11c2]?)1(?:가|\u1100\u1161)(?:[ㄴﾤ]|[나-닣]|\u1102[\u1161-\u1175][\u11a8-\u11c2]?)", false},
+		{"Standalone batchim char / choseong=true", args{"ㄻㅄ", false, false, true, false}, "(?:[ㄹﾩ]|[라-맇]|\u1105[\u1161-\u1175][\u11a8-\u11c2]?)(?:[ㅁﾱ]|[마-밓]|\u1106[\u1161-\u1175][\u11a8-\u11c2]?)(?:[ㅂﾲ]|[바-빟]|\u1107[\u1161-\u1175][\u11a8-\u11c2]?)(?:[ㅅﾵ]|[사-싷]|\u1109[\u1161-\u1175][\u11a8-\u11c2]?)", false},
 
 		{"Any / ignoreSpace=true, fuzzy=true / err", args{"", true, true, false, false}, "", true},
 
 		{"Alphabet / capturing=true", args{"a", false, false, false, true}, "(a)", false},
-		{"Hangul / capturing=true", args{"ㄱ나다라123", false, false, false, true}, "(ㄱ)(나)(다)(라)(1)(2)(3)", false},
-		{"Mixed / capturing=true", args{"Zx0ㅡㅡ", false, false, false, true}, "(Z)(x)(0)(ㅡ)(ㅡ)", false},
-		{"Special chars / capturing=true", args{"[^가-힣]$", false, false, false, true}, "(\\[)(\\^)(가)(-)(힣)(])(\\$)", false},
+		{"Hangul / capturing=true", args{"ㄱ나다라123", false, false, false, true}, "([ㄱﾡ])(나|\u1102\u1161)(다|\u1103\u1161)(라|\u1105\u1161)(1)(2)(3)", false},
+		{"Mixed / capturing=true", args{"Zx0ㅡㅡ", false, false, false, true}, "(Z)(x)(0)([ㅡￚ])([ㅡￚ])", false},
+		{"Special chars / capturing=true", args{"[^가-힣]$", false, false, false, true}, "(\\[)(\\^)(가|\u1100\u1161)(-)(힣|\u1112\u1175\u11c2)(])(\\$)", false},
 
-		{"Last char with batchim / capturing=true", args{"가 안", false, false, false, true}, "(가)( )(?:(안)|(아)(ㄴ|[나-닣]))", false},
-		{"Last char with double batchim / capturing=true", args{"가 있", false, false, false, true}, "(가)( )(?:(있)|(이)(ㅆ|[싸-앃]))", false},
-		{"Last char with combined batchim / capturing=true", args{"가 얇", false, false, false, true}, "(가)( )(?:(얇)|(얄)(ㅂ|[바-빟]))", false},
-		{"Last char is combined choseong / capturing=true", args{"ㄻ", false, false, false, true}, "(ㄻ)", false},
+		{"Last char with batchim / capturing=true", args{"가 안", false, false, false, true}, "(가|\u1100\u1161)( )(?:([안-않]|\u110b\u1161[\u11ab-\u11ad])|(아|\u110b\u1161)([ㄴﾤ]|[나-닣]|\u1102[\u1161-\u1175][\u11a8-\u11c2]?))", false},
+		{"Last char with double batchim / capturing=true", args{"가 있", false, false, false, true}, "(가|\u1100\u1161)( )(?:(있|\u110b\u1175\u11bb)|(이|\u110b\u1175)([ㅆﾶ]|[싸-앃]|\u110a[\u1161-\u1175][\u11a8-\u11c2]?))", false},
+		{"Last char with combined batchim / capturing=true", args{"가 얇", false, false, false, true}, "(가|\u1100\u1161)( )(?:(얇|\u110b\u1163\u11b2)|(얄|\u110b\u1163\u11af)([ㅂﾲ]|[바-빟]|\u1107[\u1161-\u1175][\u11a8-\u11c2]?))", false},
+		{"Last char is combined choseong / capturing=true", args{"ㄻ", false, false, false, true}, "([ㄻﾫ])", false},
 
-		{"Mixed / fuzzy=true, capturing=true", args{"ㅁ가a항1", false, true, false, true}, "(ㅁ).*?(가).*?(a).*?(항).*?(1)", false},
-		{"Space / fuzzy=true, capturing=true", args{"가 s", false, true, false, true}, "(가).*?( ).*?(s)", false},
-		{"Last char with batchim / fuzzy=true, capturing=true", args{"가 안", false, true, false, true}, "(가).*?( ).*?(?:(안)|(아).*?(ㄴ|[나-닣]))", false},
+		{"Mixed / fuzzy=true, capturing=true", args{"ㅁ가a항1", false, true, false, true}, "([ㅁﾱ]).*?(가|\u1100\u1161).*?(a).*?(항|\u1112\u1161\u11bc).*?(1)", false},
+		{"Space / fuzzy=true, capturing=true", args{"가 s", false, true, false, true}, "(가|\u1100\u1161).*?( ).*?(s)", false},
+		{"Last char with batchim / fuzzy=true, capturing=true", args{"가 안", false, true, false, true}, "(가|\u1100\u1161).*?( ).*?(?:([안-않]|\u110b\u1161[\u11ab-\u11ad])|(아|\u110b\u1161).*?([ㄴﾤ]|[나-닣]|\u1102[\u1161-\u1175][\u11a8-\u11c2]?))", false},
 
-		{"Standalone batchim char / choseong=true, capturing=true", args{"ㄻㅄ", false, false, true, true}, "(ㄹ|[라-맇])(ㅁ|[마-밓])(ㅂ|[바-빟])(ㅅ|[사-싷])", false},
+		{"Standalone batchim char / choseong=true, capturing=true", args{"ㄻㅄ", false, false, true, true}, "([ㄹﾩ]|[라-맇]|\u1105[\u1161-\u1175][\u11a8-\u11c2]?)([ㅁﾱ]|[마-밓]|\u1106[\u1161-\u1175][\u11a8-\u11c2]?)([ㅂﾲ]|[바-빟]|\u1107[\u1161-\u1175][\u11a8-\u11c2]?)([ㅅﾵ]|[사-싷]|\u1109[\u1161-\u1175][\u11a8-\u11c2]?)", false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -78,6 +101,143 @@ func TestGetPattern(t *testing.T) {
 	}
 }
 
+func TestPattern(t *testing.T) {
+	tests := []struct {
+		name    string
+		search  string
+		opts    []Option
+		want    string
+		wantErr bool
+	}{
+		{"No options", "가 안", nil, "(?:가|\u1100\u1161) (?:(?:[안-않]|\u110b\u1161[\u11ab-\u11ad])|(?:아|\u110b\u1161)(?:[ㄴﾤ]|[나-닣]|\u1102[\u1161-\u1175][\u11a8-\u11c2]?))", false},
+		{"Options value", "ㄱ1", []Option{Options{MatchChoseong: true, Capturing: true}}, "([ㄱﾡ]|[가-깋]|\u1100[\u1161-\u1175][\u11a8-\u11c2]?)(1)", false},
+		{"Functional options", "ㄱ1", []Option{WithChoseong(), WithCapturing()}, "([ㄱﾡ]|[가-깋]|\u1100[\u1161-\u1175][\u11a8-\u11c2]?)(1)", false},
+		{"Options value then functional option", "ㅁ가", []Option{Options{Capturing: true}, WithFuzzy()}, "([ㅁﾱ]).*?(가|[각-갛]|\u1100\u1161[\u11a8-\u11c2]?)", false},
+		{"Functional option then options value overrides", "ㅁ가", []Option{WithFuzzy(), Options{IgnoreSpace: true}}, "[ㅁﾱ]" + spaceGap + "(?:가|[각-갛]|\u1100\u1161[\u11a8-\u11c2]?)", false},
+		{"Raw jamo", "ㄱㅏㄴㅏㄷ", []Option{WithRawJamo()}, "(?:가|\u1100\u1161)(?:(?:낟|\u1102\u1161\u11ae)|(?:나|\u1102\u1161)(?:[ㄷﾧ]|[다-딯]|\u1103[\u1161-\u1175][\u11a8-\u11c2]?))", false},
+		{"Raw jamo with ambiguous final consonant", "ㄱㅏㄴ", []Option{WithRawJamo()}, "(?:(?:[간-갆]|\u1100\u1161[\u11ab-\u11ad])|(?:가|\u1100\u1161)(?:[ㄴﾤ]|[나-닣]|\u1102[\u1161-\u1175][\u11a8-\u11c2]?))", false},
+		{"Raw jamo with trailing jungseong", "ㄱㅏㄴㅏ", []Option{WithRawJamo()}, "(?:가|\u1100\u1161)(?:나|[낙-낳]|\u1102\u1161[\u11a8-\u11c2]?)", false},
+		{"English keyboard", "rk", []Option{WithEnglishKeyboard()}, "(?:rk|(?:가|[각-갛]|\u1100\u1161[\u11a8-\u11c2]?))", false},
+		{"English keyboard / capturing=true", "ek1", []Option{WithEnglishKeyboard(), WithCapturing()}, "(?:(e)(k)(1)|(다|\u1103\u1161)(1))", false},
+		{"English keyboard / no letters", "가1", []Option{WithEnglishKeyboard()}, "(?:가|\u1100\u1161)1", false},
+		{"Korean keyboard", "ㅡㅔ5", []Option{WithKoreanKeyboard()}, "(?:[ㅡￚ][ㅔￇ]5|[mM][pP]5)", false},
+		{"Korean keyboard / capturing=true", "와", []Option{WithKoreanKeyboard(), WithCapturing()}, "(?:(와|[왁-왛]|\u110b\u116a[\u11a8-\u11c2]?)|([dD])([hH])([kK]))", false},
+		{"Korean keyboard / no Hangul", "a1", []Option{WithKoreanKeyboard()}, "a1", false},
+		{"Gap chars", "가나", []Option{WithGap(Gap{Chars: "·-_"})}, "(?:가|\u1100\u1161)[·\\-_]*?(?:나|[낙-낳]|\u1102\u1161[\u11a8-\u11c2]?)", false},
+		{"Gap single char", "가나", []Option{WithGap(Gap{Chars: "."})}, "(?:가|\u1100\u1161)\\.*?(?:나|[낙-낳]|\u1102\u1161[\u11a8-\u11c2]?)", false},
+		{"Gap whitespace with max", "가나", []Option{WithGap(Gap{Whitespace: true, Max: 2})}, "(?:가|\u1100\u1161)[\\t-\\r\\x{85}\\p{Z}]{0,2}?(?:나|[낙-낳]|\u1102\u1161[\u11a8-\u11c2]?)", false},
+		{"Gap any with max", "가나", []Option{WithGap(Gap{Any: true, Max: 3})}, "(?:가|\u1100\u1161).{0,3}?(?:나|[낙-낳]|\u1102\u1161[\u11a8-\u11c2]?)", false},
+		{"Gap with ignoreSpace", "가나", []Option{WithIgnoreSpace(), WithGap(Gap{Chars: "·"})}, "(?:가|\u1100\u1161)[\\t-\\r\\x{85}\\p{Z}\\x{200B}-\\x{200D}\\x{2060}\\x{FEFF}·]*?(?:나|[낙-낳]|\u1102\u1161[\u11a8-\u11c2]?)", false},
+		{"Gap max out of range / err", "가", []Option{WithGap(Gap{Any: true, Max: 1001})}, "", true},
+		{"Fold case and width", "mp５-가", []Option{WithFoldCaseAndWidth()}, "[mｍMＭ][pｐPＰ][５5][\\-－](?:가|[각-갛]|\u1100\u1161[\u11a8-\u11c2]?)", false},
+		{"Fold case and width / Korean keyboard", "ㅡ", []Option{WithFoldCaseAndWidth(), WithKoreanKeyboard()}, "(?:(?:[ㅡￚ]|[으-읳]|\u110b[\u1173-\u1174][\u11a8-\u11c2]?)|[mMｍＭ])", false},
+		{"Relax tense", "가다", []Option{WithRelaxTense()}, "(?:[가까]|[\u1100-\u1101]\u1161)(?:[다따]|[닥-닿딱-땋]|[\u1103-\u1104]\u1161[\u11a8-\u11c2]?)", false},
+		{"Relax tense / choseong", "ㅅ", []Option{WithRelaxTense()}, "(?:[ㅅㅆﾵﾶ]|[사-싷싸-앃]|[\u1109-\u110a][\u1161-\u1175][\u11a8-\u11c2]?)", false},
+		{"Relax tense / batchim", "각나", []Option{WithRelaxTense()}, "(?:[각갂깍깎]|[\u1100-\u1101]\u1161[\u11a8-\u11a9])(?:나|[낙-낳]|\u1102\u1161[\u11a8-\u11c2]?)", false},
+		{"Relax tense / last batchim", "각", []Option{WithRelaxTense()}, "(?:(?:[갂깎]|[각갃깍깏]|[\u1100-\u1101]\u1161[\u11a8-\u11aa])|(?:[가까]|[\u1100-\u1101]\u1161)(?:[ㄱㄲﾡﾢ]|[가-깋까-낗]|[\u1100-\u1101][\u1161-\u1175][\u11a8-\u11c2]?))", false},
+		{"Relax tense and aspirated", "갑ㅏ", []Option{WithRelaxTense(), WithRelaxAspirated()}, "(?:(?:[갑갚깝깦캅캎]|[\u1100-\u1101\u110f]\u1161[\u11b8\u11c1])[ㅏￂ]|(?:[가까카]|[\u1100-\u1101\u110f]\u1161)(?:[바-밯빠-빻파-팧]|[\u1107-\u1108\u1111]\u1161[\u11a8-\u11c2]?))", false},
+		{"Anchor prefix", "거", []Option{WithAnchor(AnchorPrefix)}, "^(?:거|[걱-겋]|\u1100\u1165[\u11a8-\u11c2]?)", false},
+		{"Anchor full", "가나", []Option{WithAnchor(AnchorFull), WithFuzzy()}, "^(?:가|\u1100\u1161).*?(?:나|[낙-낳]|\u1102\u1161[\u11a8-\u11c2]?)$", false},
+		{"Anchor full / multiple branches", "rk", []Option{WithAnchor(AnchorFull), WithEnglishKeyboard()}, "^(?:rk|(?:가|[각-갛]|\u1100\u1161[\u11a8-\u11c2]?))$", false},
+		{"Anchor word start", "ㄱ", []Option{WithAnchor(AnchorWordStart)}, "(?:^|[^\\p{L}\\p{Nd}])(?:[ㄱﾡ]|[가-깋]|\u1100[\u1161-\u1175][\u11a8-\u11c2]?)", false},
+		{"Unknown anchor / err", "가", []Option{WithAnchor(7)}, "", true},
+		{"Unknown engine / err", "가", []Option{WithEngine(5)}, "", true},
+		{"ignoreSpace and fuzzy / err", "가", []Option{WithIgnoreSpace(), WithFuzzy()}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Pattern(tt.search, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Pattern() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Pattern() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		search  string
+		opts    []Option
+		target  string
+		want    bool
+		wantErr bool
+	}{
+		{"마깃아", []Option{WithFuzzy()}, "마력이 깃든 안대", true, false},
+		{"ㅇㅋㅇ", []Option{WithChoseong()}, "아케인셰이드 스태프", true, false},
+		{"ㅇㅋㅇ", nil, "아케인셰이드 스태프", false, false},
+		{"고", nil, "과자", true, false},
+		{"mp5", []Option{WithFoldCaseAndWidth()}, "ＭＰ５", true, false},
+		{"ＭＰ5", []Option{WithFoldCaseAndWidth()}, "mp５", true, false},
+		{"mp5", nil, "MP5", false, false},
+		{"가치", []Option{WithRelaxTense()}, "까치", true, false},
+		{"ㅅㄱ", []Option{WithRelaxTense(), WithChoseong()}, "쌍꺼풀", true, false},
+		{"박", []Option{WithRelaxTense()}, "밖", true, false},
+		{"박", []Option{WithRelaxTense()}, "밬", false, false},
+		{"박", []Option{WithRelaxAspirated()}, "밬", true, false},
+		{"까치", []Option{WithRelaxTense()}, "가치", false, false},
+		{"아케인 셰이드", []Option{WithIgnoreSpace()}, "아케인\u200b셰이드", true, false},
+		{"아케인셰이드", []Option{WithIgnoreSpace()}, "아케인\t\u00a0\u3000셰이드", true, false},
+		{"갑 ㅏ", []Option{WithIgnoreSpace()}, "가바", false, false},
+		{"갑 ㅏ", []Option{WithIgnoreSpace()}, "갑 아", true, false},
+		{"가 ㅏ", []Option{WithIgnoreSpace()}, "가 아", true, false},
+		{"가 ㅏ", nil, "가 아", true, false},
+		{"가 ", []Option{WithIgnoreSpace()}, "각", false, false},
+		{"아케인셰이드", []Option{WithGap(Gap{Chars: "·-_"})}, "아케인·셰이드", true, false},
+		{"아케인셰이드", []Option{WithGap(Gap{Chars: "·-_"})}, "아케인-셰이드", true, false},
+		{"아케인셰이드", []Option{WithGap(Gap{Chars: "·-_"})}, "아케인 셰이드", false, false},
+		{"아케인셰이드", []Option{WithGap(Gap{Any: true, Max: 1})}, "아케인--셰이드", false, false},
+		{"그", nil, "의자", false, false},
+		{"그", nil, "긔", true, false},
+		{"갈", nil, "갉", true, false},
+		{"갈", nil, "갊", true, false},
+		{"갈", nil, "갆", false, false},
+		{"(a|b)*", nil, "(a|b)*", true, false},
+		{"가나", nil, DecomposeString("가나다"), true, false},
+		{"가나", nil, DecomposeString("각나"), false, false},
+		{"갈", nil, DecomposeString("갉"), true, false},
+		{"ㅇㅋㅇ", []Option{WithChoseong()}, DecomposeString("아케인"), true, false},
+		{"까치", []Option{WithRelaxTense()}, DecomposeString("까치"), true, false},
+		{"ㄱ", []Option{WithChoseong()}, "ﾡ", true, false},
+		{"ㅇㅋㅇ", []Option{WithChoseong()}, "ﾷﾻﾷ", true, false},
+		{"ㄱㅏ", nil, "ﾡￂ", true, false},
+		{"가", []Option{WithIgnoreSpace(), WithFuzzy()}, "", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.search+" / "+tt.target, func(t *testing.T) {
+			regex, err := Compile(tt.search, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Compile() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil {
+				if !strings.Contains(err.Error(), tt.search) {
+					t.Errorf("Compile() error = %v, want search %q in error", err, tt.search)
+				}
+				if !errors.Is(err, ErrIgnoreSpaceAndFuzzy) {
+					t.Errorf("Compile() error = %v, want %v", err, ErrIgnoreSpaceAndFuzzy)
+				}
+				return
+			}
+			if got := regex.MatchString(tt.target); got != tt.want {
+				t.Errorf("Compile() MatchString() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMustCompilePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("MustCompile() did not panic")
+		}
+	}()
+	MustCompile("가", WithIgnoreSpace(), WithFuzzy())
+}
+
 func TestGetPatternCapturing(t *testing.T) {
 	tests := []struct {
 		search      string
@@ -144,6 +304,78 @@ func BenchmarkGetPattern_ㅇㅋㅇㅅㅇㄷ_ㅇㄴㅈㅅㄷ_fuzzy_matchChoseong(
 	}
 }
 
+var benchmarkTargets = func() []string {
+	names := []string{
+		"아케인셰이드 에너지소드", "아케인셰이드 스태프", "아케인셰이드 투핸드소드", "앱솔랩스 브로드세이버", "앱솔랩스 스펠링스태프",
+		"마력이 깃든 안대", "루즈 컨트롤 머신 마크", "이글아이 레인저슈트", "트릭스터 레인저팬츠", "하이네스 레인저베레모",
+		"파프니르 페니텐시아", "에테르넬 나이트헬름", "제네시스 에너지소드", "블랙빈 마크", "몽환의 벨트",
+		"분노한 자쿰의 벨트", "여명의 가디언 엔젤 링", "고귀한 이피아의 반지", "칠흑의 보스 세트", "거대한 공포",
+	}
+	targets := make([]string, 0, len(names)*50)
+	for i := 0; i < 50; i++ {
+		targets = append(targets, names...)
+	}
+	return targets
+}()
+
+func benchmarkMatcherFilter(b *testing.B, search string, opts ...Option) {
+	for i := 0; i < b.N; i++ {
+		m := MustNewMatcher(search, opts...)
+		_ = m.Filter(benchmarkTargets)
+	}
+}
+
+func BenchmarkMatcher_Regexp_마깃안(b *testing.B) {
+	benchmarkMatcherFilter(b, "마깃안")
+}
+
+func BenchmarkMatcher_Native_마깃안(b *testing.B) {
+	benchmarkMatcherFilter(b, "마깃안", WithEngine(EngineNative))
+}
+
+func BenchmarkMatcher_Regexp_마깃안_fuzzy(b *testing.B) {
+	benchmarkMatcherFilter(b, "마깃안", WithFuzzy())
+}
+
+func BenchmarkMatcher_Native_마깃안_fuzzy(b *testing.B) {
+	benchmarkMatcherFilter(b, "마깃안", WithFuzzy(), WithEngine(EngineNative))
+}
+
+func BenchmarkMatcher_Regexp_아케인셰이드_에너지소드_ignoreSpace(b *testing.B) {
+	benchmarkMatcherFilter(b, "아케인셰이드 에너지소드", WithIgnoreSpace())
+}
+
+func BenchmarkMatcher_Native_아케인셰이드_에너지소드_ignoreSpace(b *testing.B) {
+	benchmarkMatcherFilter(b, "아케인셰이드 에너지소드", WithIgnoreSpace(), WithEngine(EngineNative))
+}
+
+func BenchmarkMatcher_Regexp_ㅇㅋㅇㅅㅇㄷ_fuzzy_matchChoseong(b *testing.B) {
+	benchmarkMatcherFilter(b, "ㅇㅋㅇㅅㅇㄷ", WithFuzzy(), WithChoseong())
+}
+
+func BenchmarkMatcher_Native_ㅇㅋㅇㅅㅇㄷ_fuzzy_matchChoseong(b *testing.B) {
+	benchmarkMatcherFilter(b, "ㅇㅋㅇㅅㅇㄷ", WithFuzzy(), WithChoseong(), WithEngine(EngineNative))
+}
+
+func benchmarkIndexSearch(b *testing.B, search string, opts ...Option) {
+	idx := NewIndex()
+	for i, target := range benchmarkTargets {
+		idx.Add(i, target)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = idx.Search(search, opts...)
+	}
+}
+
+func BenchmarkIndex_ㅇㅋㅇㅅㅇㄷ_fuzzy_matchChoseong(b *testing.B) {
+	benchmarkIndexSearch(b, "ㅇㅋㅇㅅㅇㄷ", WithFuzzy(), WithChoseong())
+}
+
+func BenchmarkIndex_마깃안_fuzzy(b *testing.B) {
+	benchmarkIndexSearch(b, "마깃안", WithFuzzy())
+}
+
 func BenchmarkLastHangulString_Sprint(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		_ = fmt.Sprint("(?:",
@@ -245,7 +477,7 @@ func benchmarkBetweenFunction_PassBuilder_Inner(builder strings.Builder) {
 func BenchmarkGetChoseongOffset_BinarySearch(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		for ci := range choseongs {
-			slices.BinarySearch(choseongs[:], choseongs[ci])
+			_, _ = slices.BinarySearch(choseongs[:], choseongs[ci])
 		}
 	}
 }
@@ -253,7 +485,7 @@ func BenchmarkGetChoseongOffset_BinarySearch(b *testing.B) {
 func BenchmarkGetChoseongOffset_Linear(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		for ci := range choseongs {
-			slices.Index(choseongs[:], choseongs[ci])
+			_ = slices.Index(choseongs[:], choseongs[ci])
 		}
 	}
 }
@@ -316,7 +548,7 @@ func BenchmarkGetChoseongOffset_Switch(b *testing.B) {
 func BenchmarkGetJongseongOffset_BinarySearch(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		for ci := range jongseongs {
-			slices.BinarySearch(jongseongs[:], jongseongs[ci])
+			_, _ = slices.BinarySearch(jongseongs[:], jongseongs[ci])
 		}
 	}
 }
@@ -324,7 +556,7 @@ func BenchmarkGetJongseongOffset_BinarySearch(b *testing.B) {
 func BenchmarkGetJongseongOffset_Linear(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		for ci := range jongseongs {
-			slices.Index(jongseongs[:], jongseongs[ci])
+			_ = slices.Index(jongseongs[:], jongseongs[ci])
 		}
 	}
 }
@@ -5,9 +5,59 @@ func IsHangul(ch rune) bool {
 }
 
 func CanBeChoseongOrJongseong(ch rune) bool {
+	ch = compatJamo(ch)
 	return 'ㄱ' <= ch && ch <= 'ㅎ'
 }
 
+func IsJungseong(ch rune) bool {
+	ch = compatJamo(ch)
+	return 'ㅏ' <= ch && ch <= 'ㅣ'
+}
+
+func IsHalfwidthJamo(ch rune) bool {
+	return HalfwidthToCompat(ch) >= 0
+}
+
+// HalfwidthToCompat returns the compatibility jamo of a halfwidth jamo, e.g.
+// 'ﾡ' (U+FFA1) becomes 'ㄱ', or -1.
+func HalfwidthToCompat(ch rune) rune {
+	switch {
+	case ch == '\uFFA0':
+		return '\u3164'
+	case 'ﾡ' <= ch && ch <= 'ﾾ':
+		return 'ㄱ' + ch - 'ﾡ'
+	case 'ￂ' <= ch && ch <= 'ￜ' && ch%8 >= 2:
+		// The halfwidth vowels are in rows of six, each starting at an offset
+		// of 2 modulo 8.
+		i := ch - 'ￂ'
+		return 'ㅏ' + i - i/8*2
+	}
+	return -1
+}
+
+// CompatToHalfwidth returns the halfwidth jamo of a compatibility jamo, e.g.
+// 'ㄱ' becomes 'ﾡ' (U+FFA1), or -1.
+func CompatToHalfwidth(ch rune) rune {
+	switch {
+	case ch == '\u3164':
+		return '\uFFA0'
+	case 'ㄱ' <= ch && ch <= 'ㅎ':
+		return 'ﾡ' + ch - 'ㄱ'
+	case 'ㅏ' <= ch && ch <= 'ㅣ':
+		i := ch - 'ㅏ'
+		return 'ￂ' + i + i/6*2
+	}
+	return -1
+}
+
+// compatJamo returns the compatibility jamo of a halfwidth jamo, or ch.
+func compatJamo(ch rune) rune {
+	if compat := HalfwidthToCompat(ch); compat >= 0 {
+		return compat
+	}
+	return ch
+}
+
 func CanBeChoseong(ch rune) bool {
 	return GetChoseongOffset(ch) >= 0
 }
@@ -66,6 +116,61 @@ func GetChoseongOffset(choseong rune) int {
 	case 'ㅎ':
 		return 18
 	default:
+		if compat := HalfwidthToCompat(choseong); compat >= 0 {
+			return GetChoseongOffset(compat)
+		}
+		return -1
+	}
+}
+
+func GetJungseongOffset(jungseong rune) int {
+	switch jungseong {
+	case 'ㅏ':
+		return 0
+	case 'ㅐ':
+		return 1
+	case 'ㅑ':
+		return 2
+	case 'ㅒ':
+		return 3
+	case 'ㅓ':
+		return 4
+	case 'ㅔ':
+		return 5
+	case 'ㅕ':
+		return 6
+	case 'ㅖ':
+		return 7
+	case 'ㅗ':
+		return 8
+	case 'ㅘ':
+		return 9
+	case 'ㅙ':
+		return 10
+	case 'ㅚ':
+		return 11
+	case 'ㅛ':
+		return 12
+	case 'ㅜ':
+		return 13
+	case 'ㅝ':
+		return 14
+	case 'ㅞ':
+		return 15
+	case 'ㅟ':
+		return 16
+	case 'ㅠ':
+		return 17
+	case 'ㅡ':
+		return 18
+	case 'ㅢ':
+		return 19
+	case 'ㅣ':
+		return 20
+	default:
+		if compat := HalfwidthToCompat(jungseong); compat >= 0 {
+			return GetJungseongOffset(compat)
+		}
 		return -1
 	}
 }
@@ -129,12 +234,15 @@ func GetJongseongOffset(jongseong rune) int {
 	case 'ㅎ':
 		return 27
 	default:
+		if compat := HalfwidthToCompat(jongseong); compat >= 0 {
+			return GetJongseongOffset(compat)
+		}
 		return -1
 	}
 }
 
 func SplitJongseong(jongseong rune) (rune, rune) {
-	switch jongseong {
+	switch compatJamo(jongseong) {
 	case 'ㄳ':
 		return 'ㄱ', 'ㅅ'
 	case 'ㄵ':
@@ -161,6 +269,107 @@ func SplitJongseong(jongseong rune) (rune, rune) {
 	panic(jongseong)
 }
 
+func SplitJungseong(jungseong rune) (rune, rune) {
+	switch compatJamo(jungseong) {
+	case 'ㅘ':
+		return 'ㅗ', 'ㅏ'
+	case 'ㅙ':
+		return 'ㅗ', 'ㅐ'
+	case 'ㅚ':
+		return 'ㅗ', 'ㅣ'
+	case 'ㅝ':
+		return 'ㅜ', 'ㅓ'
+	case 'ㅞ':
+		return 'ㅜ', 'ㅔ'
+	case 'ㅟ':
+		return 'ㅜ', 'ㅣ'
+	case 'ㅢ':
+		return 'ㅡ', 'ㅣ'
+	}
+	panic(jungseong)
+}
+
+func CombineJongseong(first, second rune) rune {
+	second = compatJamo(second)
+	switch compatJamo(first) {
+	case 'ㄱ':
+		if second == 'ㅅ' {
+			return 'ㄳ'
+		}
+	case 'ㄴ':
+		switch second {
+		case 'ㅈ':
+			return 'ㄵ'
+		case 'ㅎ':
+			return 'ㄶ'
+		}
+	case 'ㄹ':
+		switch second {
+		case 'ㄱ':
+			return 'ㄺ'
+		case 'ㅁ':
+			return 'ㄻ'
+		case 'ㅂ':
+			return 'ㄼ'
+		case 'ㅅ':
+			return 'ㄽ'
+		case 'ㅌ':
+			return 'ㄾ'
+		case 'ㅍ':
+			return 'ㄿ'
+		case 'ㅎ':
+			return 'ㅀ'
+		}
+	case 'ㅂ':
+		if second == 'ㅅ' {
+			return 'ㅄ'
+		}
+	}
+	return -1
+}
+
+func CombineJungseong(first, second rune) rune {
+	second = compatJamo(second)
+	switch compatJamo(first) {
+	case 'ㅗ':
+		switch second {
+		case 'ㅏ':
+			return 'ㅘ'
+		case 'ㅐ':
+			return 'ㅙ'
+		case 'ㅣ':
+			return 'ㅚ'
+		}
+	case 'ㅜ':
+		switch second {
+		case 'ㅓ':
+			return 'ㅝ'
+		case 'ㅔ':
+			return 'ㅞ'
+		case 'ㅣ':
+			return 'ㅟ'
+		}
+	case 'ㅡ':
+		if second == 'ㅣ' {
+			return 'ㅢ'
+		}
+	}
+	return -1
+}
+
+// lastCompoundJungseongOffset returns the offset of the last compound vowel
+// that starts with the vowel at jungOffset, or jungOffset if there is none.
+// Compound vowels sharing a first vowel directly follow it in jungseongs.
+func lastCompoundJungseongOffset(jungOffset int) int {
+	last := jungOffset
+	for _, second := range jungseongs {
+		if combined := CombineJungseong(jungseongs[jungOffset], second); combined >= 0 {
+			last = max(last, GetJungseongOffset(combined))
+		}
+	}
+	return last
+}
+
 var choseongs = [...]rune{'ㄱ', 'ㄲ', 'ㄴ', 'ㄷ', 'ㄸ', 'ㄹ', 'ㅁ', 'ㅂ', 'ㅃ', 'ㅅ', 'ㅆ', 'ㅇ', 'ㅈ', 'ㅉ', 'ㅊ', 'ㅋ', 'ㅌ', 'ㅍ', 'ㅎ'}
 var jungseongs = [...]rune{'ㅏ', 'ㅐ', 'ㅑ', 'ㅒ', 'ㅓ', 'ㅔ', 'ㅕ', 'ㅖ', 'ㅗ', 'ㅘ', 'ㅙ', 'ㅚ', 'ㅛ', 'ㅜ', 'ㅝ', 'ㅞ', 'ㅟ', 'ㅠ', 'ㅡ', 'ㅢ', 'ㅣ'}
 var jongseongs = [...]rune{-1, 'ㄱ', 'ㄲ', 'ㄳ', 'ㄴ', 'ㄵ', 'ㄶ', 'ㄷ', 'ㄹ', 'ㄺ', 'ㄻ', 'ㄼ', 'ㄽ', 'ㄾ', 'ㄿ', 'ㅀ', 'ㅁ', 'ㅂ', 'ㅄ', 'ㅅ', 'ㅆ', 'ㅇ', 'ㅈ', 'ㅊ', 'ㅋ', 'ㅌ', 'ㅍ', 'ㅎ'}
@@ -0,0 +1,74 @@
+package hangul_regexp
+
+import "testing"
+
+func TestHalfwidthToCompat(t *testing.T) {
+	tests := []struct {
+		ch   rune
+		want rune
+	}{
+		{'ﾡ', 'ㄱ'},
+		{'ﾪ', 'ㄺ'},
+		{'ﾾ', 'ㅎ'},
+		{'ￂ', 'ㅏ'},
+		{'ￇ', 'ㅔ'},
+		{'ￊ', 'ㅕ'},
+		{'ￒ', 'ㅛ'},
+		{'ￗ', 'ㅠ'},
+		{'ￚ', 'ㅡ'},
+		{'ￜ', 'ㅣ'},
+		{'￈', -1},
+		{'￝', -1},
+		{'ㄱ', -1},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.ch), func(t *testing.T) {
+			if got := HalfwidthToCompat(tt.ch); got != tt.want {
+				t.Errorf("HalfwidthToCompat() got = %U, want %U", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompatToHalfwidth(t *testing.T) {
+	for ch := 'ㄱ'; ch <= 'ㅤ'; ch++ {
+		if got := HalfwidthToCompat(CompatToHalfwidth(ch)); got != ch {
+			t.Errorf("HalfwidthToCompat(CompatToHalfwidth(%U)) got = %U", ch, got)
+		}
+	}
+	if got := CompatToHalfwidth('ㅥ'); got != -1 {
+		t.Errorf("CompatToHalfwidth() got = %U, want -1", got)
+	}
+}
+
+func TestHalfwidthSplitAndCombine(t *testing.T) {
+	if first, second := SplitJongseong('ﾣ'); first != 'ㄱ' || second != 'ㅅ' {
+		t.Errorf("SplitJongseong() got = %c %c, want ㄱ ㅅ", first, second)
+	}
+	if first, second := SplitJungseong('ￍ'); first != 'ㅗ' || second != 'ㅏ' {
+		t.Errorf("SplitJungseong() got = %c %c, want ㅗ ㅏ", first, second)
+	}
+	if got := CombineJongseong('ﾡ', 'ﾵ'); got != 'ㄳ' {
+		t.Errorf("CombineJongseong() got = %c, want ㄳ", got)
+	}
+	if got := CombineJungseong('ￌ', 'ￂ'); got != 'ㅘ' {
+		t.Errorf("CombineJungseong() got = %c, want ㅘ", got)
+	}
+}
+
+func TestHalfwidthOffsets(t *testing.T) {
+	for i, ch := range "ﾡﾢﾤﾧﾨﾩﾱﾲﾳﾵﾶﾷﾸﾹﾺﾻﾼﾽﾾ" {
+		if got := GetChoseongOffset(ch); got != i/3 {
+			t.Errorf("GetChoseongOffset(%U) got = %d, want %d", ch, got, i/3)
+		}
+	}
+	if got := GetJungseongOffset('ￛ'); got != 19 {
+		t.Errorf("GetJungseongOffset() got = %d, want 19", got)
+	}
+	if got := GetJongseongOffset('ﾴ'); got != 18 {
+		t.Errorf("GetJongseongOffset() got = %d, want 18", got)
+	}
+	if !CanBeChoseongOrJongseong('ﾡ') || !IsJungseong('ￂ') {
+		t.Errorf("halfwidth jamo not recognized")
+	}
+}
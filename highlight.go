@@ -0,0 +1,49 @@
+package hangul_regexp
+
+import (
+	"sort"
+	"unicode/utf8"
+)
+
+// Span is a range of byte offsets in the target.
+type Span struct {
+	Start, End int
+}
+
+// CharHighlight holds the spans of the target matched by the search
+// character at the byte offsets Start to End. A character can match multiple
+// spans, such as a syllable whose batchim starts the next target syllable.
+type CharHighlight struct {
+	Start, End int
+	Spans      []Span
+}
+
+func Highlight(search, target string, opts ...Option) ([]CharHighlight, error) {
+	m, err := NewMatcher(search, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return m.Highlight(target), nil
+}
+
+// matchedAtom is an atom of a match and the span of the target it matched.
+type matchedAtom struct {
+	a    atom
+	span Span
+}
+
+func collectHighlights(search string, matches []matchedAtom) []CharHighlight {
+	chars := make([]CharHighlight, 0, utf8.RuneCountInString(search))
+	for i, ch := range search {
+		chars = append(chars, CharHighlight{Start: i, End: i + utf8.RuneLen(ch)})
+	}
+	for _, m := range matches {
+		first := sort.Search(len(chars), func(i int) bool {
+			return chars[i].End > m.a.start
+		})
+		for i := first; i < len(chars) && chars[i].Start < m.a.end; i++ {
+			chars[i].Spans = append(chars[i].Spans, m.span)
+		}
+	}
+	return chars
+}
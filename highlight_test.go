@@ -0,0 +1,99 @@
+package hangul_regexp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name   string
+		search string
+		target string
+		opts   []Option
+		want   [][]string
+	}{
+		{"Literal", "가나", "다가나", nil, [][]string{{"가"}, {"나"}}},
+		{"Choseong", "ㅇㅋㅇ", "아케인셰이드 스태프", []Option{WithChoseong()}, [][]string{{"아"}, {"케"}, {"인"}}},
+		{"Fuzzy", "마깃아", "마력이 깃든 안대", []Option{WithFuzzy()}, [][]string{{"마"}, {"깃"}, {"안"}}},
+		{"Last char with batchim", "가안", "가안녕", nil, [][]string{{"가"}, {"안"}}},
+		{"Last char split into two syllables", "가안", "가아니", nil, [][]string{{"가"}, {"아", "니"}}},
+		{"Last char split with fuzzy", "낢", "날아 먹", []Option{WithFuzzy()}, [][]string{{"날", "먹"}}},
+		{"Trailing jungseong", "갑ㅏ", "가방", nil, [][]string{{"가", "방"}, {"방"}}},
+		{"Combined choseong", "ㅄ", "보라색", []Option{WithChoseong(), WithFuzzy()}, [][]string{{"보", "색"}}},
+		{"Capturing option is ignored", "ㄱㄴ", "가나", []Option{WithChoseong(), WithCapturing()}, [][]string{{"가"}, {"나"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Highlight(tt.search, tt.target, tt.opts...)
+			if err != nil {
+				t.Fatalf("Highlight() error = %v", err)
+			}
+			actual := make([][]string, len(got))
+			for i, ch := range got {
+				for _, span := range ch.Spans {
+					actual[i] = append(actual[i], tt.target[span.Start:span.End])
+				}
+			}
+			if !reflect.DeepEqual(actual, tt.want) {
+				t.Errorf("Highlight() got = %v, want %v", actual, tt.want)
+			}
+		})
+	}
+}
+
+func TestHighlightRawJamo(t *testing.T) {
+	got, err := Highlight("ㄱㅏㄴㅏ", "가나다", WithRawJamo())
+	if err != nil {
+		t.Fatalf("Highlight() error = %v", err)
+	}
+	want := []CharHighlight{
+		{Start: 0, End: 3, Spans: []Span{{0, 3}}},
+		{Start: 3, End: 6, Spans: []Span{{0, 3}}},
+		{Start: 6, End: 9, Spans: []Span{{3, 6}}},
+		{Start: 9, End: 12, Spans: []Span{{3, 6}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Highlight() got = %v, want %v", got, want)
+	}
+}
+
+func TestHighlightEnglishKeyboard(t *testing.T) {
+	got, err := Highlight("dkzp", "마아케인", WithEnglishKeyboard())
+	if err != nil {
+		t.Fatalf("Highlight() error = %v", err)
+	}
+	want := []CharHighlight{
+		{Start: 0, End: 1, Spans: []Span{{3, 6}}},
+		{Start: 1, End: 2, Spans: []Span{{3, 6}}},
+		{Start: 2, End: 3, Spans: []Span{{6, 9}}},
+		{Start: 3, End: 4, Spans: []Span{{6, 9}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Highlight() got = %v, want %v", got, want)
+	}
+}
+
+func TestHighlightNoMatch(t *testing.T) {
+	got, err := Highlight("가", "나")
+	if err != nil {
+		t.Fatalf("Highlight() error = %v", err)
+	}
+	if got != nil {
+		t.Errorf("Highlight() got = %v, want nil", got)
+	}
+}
+
+func TestHighlightOffsets(t *testing.T) {
+	got, err := Highlight("a안", "xa아니")
+	if err != nil {
+		t.Fatalf("Highlight() error = %v", err)
+	}
+	want := []CharHighlight{
+		{Start: 0, End: 1, Spans: []Span{{1, 2}}},
+		{Start: 1, End: 4, Spans: []Span{{2, 5}, {5, 8}}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Highlight() got = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,222 @@
+package hangul_regexp
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Index holds candidates to search repeatedly. Each candidate is stored with
+// its choseong string, which replaces each Hangul syllable with its choseong,
+// so that candidates lacking the choseongs of a search are skipped without
+// matching them. It is safe for concurrent use.
+type Index struct {
+	mu      sync.RWMutex
+	entries map[int]indexEntry
+	// postings holds the ids of the candidates containing each key.
+	postings map[rune]map[int]struct{}
+}
+
+type indexEntry struct {
+	text string
+	keys []rune
+}
+
+func NewIndex() *Index {
+	return &Index{
+		entries:  make(map[int]indexEntry),
+		postings: make(map[rune]map[int]struct{}),
+	}
+}
+
+// Add adds the text as the candidate id, replacing any candidate with the
+// same id.
+func (idx *Index) Add(id int, text string) {
+	text, _ = normalizeJamo(text)
+	keys := make([]rune, 0, len(text))
+	for _, ch := range text {
+		keys = append(keys, indexKey(ch))
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(id)
+	idx.entries[id] = indexEntry{text: text, keys: keys}
+	for _, key := range keys {
+		ids, ok := idx.postings[key]
+		if !ok {
+			ids = make(map[int]struct{})
+			idx.postings[key] = ids
+		}
+		ids[id] = struct{}{}
+	}
+}
+
+// Remove removes the candidate id, and reports whether it was present.
+func (idx *Index) Remove(id int) bool {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	return idx.remove(id)
+}
+
+func (idx *Index) remove(id int) bool {
+	entry, ok := idx.entries[id]
+	if !ok {
+		return false
+	}
+	delete(idx.entries, id)
+	for _, key := range entry.keys {
+		if ids, ok := idx.postings[key]; ok {
+			delete(ids, id)
+			if len(ids) == 0 {
+				delete(idx.postings, key)
+			}
+		}
+	}
+	return true
+}
+
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.entries)
+}
+
+// Search returns the ids of the candidates matching the search in ascending
+// order. The candidates match as with a Matcher of the same options, and the
+// Engine option is ignored.
+func (idx *Index) Search(search string, opts ...Option) ([]int, error) {
+	o := NewOptions(opts...)
+	if err := o.Validate(); err != nil {
+		return nil, fmt.Errorf("hangul_regexp: search %q: %w", search, err)
+	}
+	branches := buildBranches(search, o, true)
+	m := newNativeMatcher(branches, o)
+	required := make([][]rune, len(branches))
+	for b, br := range branches {
+		required[b] = requiredKeys(br.segments)
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	var ids []int
+	check := func(id int) {
+		entry := idx.entries[id]
+		for _, keys := range required {
+			if isSubsequence(keys, entry.keys) {
+				if m.MatchString(entry.text) {
+					ids = append(ids, id)
+				}
+				return
+			}
+		}
+	}
+
+	candidates := idx.candidates(required)
+	if candidates == nil {
+		for id := range idx.entries {
+			check(id)
+		}
+	} else if len(candidates) == 1 {
+		for id := range candidates[0] {
+			check(id)
+		}
+	} else {
+		seen := make(map[int]struct{})
+		for _, ids := range candidates {
+			for id := range ids {
+				if _, ok := seen[id]; !ok {
+					seen[id] = struct{}{}
+					check(id)
+				}
+			}
+		}
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
+
+// candidates returns the sets of ids containing the candidates that can match
+// a branch, or nil if every candidate can match.
+func (idx *Index) candidates(required [][]rune) []map[int]struct{} {
+	candidates := []map[int]struct{}{}
+	for _, keys := range required {
+		if len(keys) == 0 {
+			return nil
+		}
+		var smallest map[int]struct{}
+		for i, key := range keys {
+			ids := idx.postings[key]
+			if i == 0 || len(ids) < len(smallest) {
+				smallest = ids
+			}
+		}
+		if len(smallest) > 0 {
+			candidates = append(candidates, smallest)
+		}
+	}
+	return candidates
+}
+
+// indexKey returns the choseong of a Hangul syllable, or the rune itself.
+func indexKey(ch rune) rune {
+	if IsHangul(ch) {
+		choOffset, _, _ := Disassemble(ch)
+		return choseongs[choOffset]
+	}
+	return ch
+}
+
+// requiredKeys returns the keys of the runes that every match of the segments
+// contains, in order. Atoms matching runes of different keys are left out.
+func requiredKeys(segments []segment) []rune {
+	var keys []rune
+	for _, seg := range segments {
+		if len(seg.alts) != 1 {
+			continue
+		}
+		for _, a := range seg.alts[0] {
+			if key, ok := atomKey(&a); ok {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
+
+// atomKey returns the key of every rune matched by the atom, or false if the
+// runes have different keys.
+func atomKey(a *atom) (rune, bool) {
+	key := rune(-1)
+	same := func(ch rune) bool {
+		if key < 0 {
+			key = indexKey(ch)
+		}
+		return indexKey(ch) == key
+	}
+	for _, lit := range a.lits {
+		if !same(lit) {
+			return 0, false
+		}
+	}
+	for _, r := range a.ranges {
+		// Hangul syllables are ordered by choseong, so the syllables between
+		// two with the same choseong share it.
+		if r.lo != r.hi && !(IsHangul(r.lo) && IsHangul(r.hi)) || !same(r.lo) || !same(r.hi) {
+			return 0, false
+		}
+	}
+	return key, key >= 0
+}
+
+func isSubsequence(sub, s []rune) bool {
+	for _, ch := range s {
+		if len(sub) == 0 {
+			break
+		}
+		if sub[0] == ch {
+			sub = sub[1:]
+		}
+	}
+	return len(sub) == 0
+}
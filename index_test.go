@@ -0,0 +1,96 @@
+package hangul_regexp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIndex(t *testing.T) {
+	idx := NewIndex()
+	idx.Add(3, "아케인셰이드 스태프")
+	idx.Add(1, "아이스 스태프")
+	idx.Add(2, "오크 완드")
+	idx.Add(5, "아케인셰이드 에너지소드")
+
+	tests := []struct {
+		name   string
+		search string
+		opts   []Option
+		want   []int
+	}{
+		{"choseong", "ㅇㅋㅇ", []Option{WithChoseong()}, []int{3, 5}},
+		{"choseong fuzzy", "ㅇㅋㅇ", []Option{WithChoseong(), WithFuzzy()}, []int{2, 3, 5}},
+		{"last syllable", "아케인셰이드 엔", nil, []int{5}},
+		{"ignoreSpace", "이드스", []Option{WithIgnoreSpace()}, []int{3}},
+		{"relax", "오그", []Option{WithRelaxAspirated(), WithFuzzy()}, []int{2}},
+		{"no match", "ㅎㅎ", []Option{WithChoseong()}, nil},
+		{"empty", "", nil, []int{1, 2, 3, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := idx.Search(tt.search, tt.opts...)
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	idx.Add(3, "칠흑의 보스 세트")
+	if !idx.Remove(5) {
+		t.Errorf("Remove() got = false, want true")
+	}
+	if idx.Remove(5) {
+		t.Errorf("Remove() got = true, want false")
+	}
+	if got := idx.Len(); got != 3 {
+		t.Errorf("Len() got = %v, want 3", got)
+	}
+	if got, _ := idx.Search("ㅇㅋㅇ", WithChoseong()); got != nil {
+		t.Errorf("Search() got = %v, want nil", got)
+	}
+	if got, _ := idx.Search("ㅊㅎ", WithChoseong()); !reflect.DeepEqual(got, []int{3}) {
+		t.Errorf("Search() got = %v, want [3]", got)
+	}
+
+	if _, err := idx.Search("가", WithIgnoreSpace(), WithFuzzy()); err == nil {
+		t.Errorf("Search() error = nil, want error")
+	}
+}
+
+func TestIndexMatchesFilter(t *testing.T) {
+	idx := NewIndex()
+	for i, target := range benchmarkTargets[:20] {
+		idx.Add(i, target)
+	}
+	searches := []string{"ㅇㅋㅇ", "ㅇㅋㅇㅅㅇㄷ ㅇㄴㅈㅅㄷ", "마깃안", "앱솔", "ㄹㅇㅈ", "dkzpdls", "rp", "", "에너지 소"}
+	optionSets := [][]Option{
+		nil,
+		{WithIgnoreSpace()},
+		{WithFuzzy()},
+		{WithChoseong()},
+		{WithFuzzy(), WithChoseong()},
+		{WithEnglishKeyboard(), WithFuzzy(), WithChoseong()},
+		{WithRelaxTense(), WithRelaxAspirated(), WithFuzzy(), WithChoseong()},
+	}
+	for _, opts := range optionSets {
+		for _, search := range searches {
+			m := MustNewMatcher(search, opts...)
+			var want []int
+			for i, target := range benchmarkTargets[:20] {
+				if m.MatchString(target) {
+					want = append(want, i)
+				}
+			}
+			got, err := idx.Search(search, opts...)
+			if err != nil {
+				t.Fatalf("Search() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("%v: Search() got = %v, want %v", m, got, want)
+			}
+		}
+	}
+}
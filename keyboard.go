@@ -0,0 +1,143 @@
+package hangul_regexp
+
+import "unicode/utf8"
+
+// KeyToJamo returns the jamo typed by the QWERTY key on a 2-beolsik keyboard,
+// or -1 if the key does not type one.
+func KeyToJamo(key rune) rune {
+	switch key {
+	case 'Q':
+		return 'ㅃ'
+	case 'W':
+		return 'ㅉ'
+	case 'E':
+		return 'ㄸ'
+	case 'R':
+		return 'ㄲ'
+	case 'T':
+		return 'ㅆ'
+	case 'O':
+		return 'ㅒ'
+	case 'P':
+		return 'ㅖ'
+	}
+	if 'A' <= key && key <= 'Z' {
+		key += 'a' - 'A'
+	}
+	if 'a' <= key && key <= 'z' {
+		return keyJamos[key-'a']
+	}
+	return -1
+}
+
+// JamoToKey returns the QWERTY key that types the jamo on a 2-beolsik
+// keyboard, or -1 if the jamo is not on the keyboard. Jamo typed with shift
+// are returned in upper case.
+func JamoToKey(jamo rune) rune {
+	jamo = compatJamo(jamo)
+	switch jamo {
+	case 'ㅃ':
+		return 'Q'
+	case 'ㅉ':
+		return 'W'
+	case 'ㄸ':
+		return 'E'
+	case 'ㄲ':
+		return 'R'
+	case 'ㅆ':
+		return 'T'
+	case 'ㅒ':
+		return 'O'
+	case 'ㅖ':
+		return 'P'
+	}
+	for i, j := range keyJamos {
+		if j == jamo {
+			return rune('a' + i)
+		}
+	}
+	return -1
+}
+
+// KeysToHangul converts keys typed on a 2-beolsik keyboard in English mode
+// into Hangul, e.g. "rkskek" becomes "가나다".
+func KeysToHangul(keys string) string {
+	hangul, _ := transliterateKeys(keys)
+	return hangul
+}
+
+// transliterateKeys returns the Hangul typed by the keys, and the byte offset
+// in keys for each of its byte offsets and its length.
+func transliterateKeys(keys string) (string, []int) {
+	jamo := make([]rune, 0, len(keys))
+	jamoSources := make([]int, 0, len(keys)*3+1)
+	for i, key := range keys {
+		if j := KeyToJamo(key); j >= 0 {
+			key = j
+		}
+		jamo = append(jamo, key)
+		for range utf8.RuneLen(key) {
+			jamoSources = append(jamoSources, i)
+		}
+	}
+	jamoSources = append(jamoSources, len(keys))
+
+	hangul, sources := assembleString(string(jamo))
+	for i, source := range sources {
+		sources[i] = jamoSources[source]
+	}
+	return hangul, sources
+}
+
+// HangulToKeys converts Hangul into the keys that type it on a 2-beolsik
+// keyboard, e.g. "가나다" becomes "rkskek". Other characters are kept as is.
+func HangulToKeys(hangul string) string {
+	keys, _ := hangulToKeys(hangul)
+	return keys
+}
+
+// hangulToKeys returns the keys typing the Hangul, and the byte offset in
+// hangul for each of its byte offsets and its length.
+func hangulToKeys(hangul string) (string, []int) {
+	keys := make([]byte, 0, len(hangul))
+	sources := make([]int, 0, len(hangul)+1)
+	writeKeys := func(jamo rune, source int) {
+		if key := JamoToKey(jamo); key >= 0 {
+			keys = append(keys, byte(key))
+			sources = append(sources, source)
+			return
+		}
+		var first, second rune
+		if IsJungseong(jamo) {
+			first, second = SplitJungseong(jamo)
+		} else {
+			first, second = SplitJongseong(jamo)
+		}
+		keys = append(keys, byte(JamoToKey(first)), byte(JamoToKey(second)))
+		sources = append(sources, source, source)
+	}
+	for i, ch := range hangul {
+		if IsHangul(ch) {
+			choOffset, jungOffset, jongOffset := Disassemble(ch)
+			writeKeys(choseongs[choOffset], i)
+			writeKeys(jungseongs[jungOffset], i)
+			if jongOffset > 0 {
+				writeKeys(jongseongs[jongOffset], i)
+			}
+		} else if CanBeChoseongOrJongseong(ch) || IsJungseong(ch) {
+			writeKeys(ch, i)
+		} else {
+			keys = utf8.AppendRune(keys, ch)
+			for range utf8.RuneLen(ch) {
+				sources = append(sources, i)
+			}
+		}
+	}
+	sources = append(sources, len(hangul))
+	return string(keys), sources
+}
+
+var keyJamos = [...]rune{
+	'ㅁ', 'ㅠ', 'ㅊ', 'ㅇ', 'ㄷ', 'ㄹ', 'ㅎ', 'ㅗ', 'ㅑ', 'ㅓ', 'ㅏ', 'ㅣ', 'ㅡ',
+	'ㅜ', 'ㅐ', 'ㅔ', 'ㅂ', 'ㄱ', 'ㄴ', 'ㅅ', 'ㅕ', 'ㅍ', 'ㅈ', 'ㅌ', 'ㅛ', 'ㅋ',
+}
@@ -0,0 +1,110 @@
+package hangul_regexp
+
+import "testing"
+
+func TestKeysToHangul(t *testing.T) {
+	tests := []struct {
+		keys string
+		want string
+	}{
+		{"", ""},
+		{"rkskek", "가나다"},
+		{"dkzpdls", "아케인"},
+		{"tkfkdgo", "사랑해"},
+		{"Rkcl", "까치"},
+		{"RKCL", "까치"},
+		{"dhk", "와"},
+		{"ekfr", "닭"},
+		{"qkqh 123", "바보 123"},
+		{"EkQkWkTkRk", "따빠짜싸까"},
+		{"dO dP", "얘 예"},
+		{"아케인", "아케인"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.keys, func(t *testing.T) {
+			if got := KeysToHangul(tt.keys); got != tt.want {
+				t.Errorf("KeysToHangul() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnglishKeyboard(t *testing.T) {
+	tests := []struct {
+		search string
+		target string
+		want   bool
+	}{
+		{"dkzpdls", "아케인셰이드 스태프", true},
+		{"dkzpdl", "아케인셰이드 스태프", true},
+		{"dkzpd", "아케인셰이드 스태프", true},
+		{"zakum", "Zakum Helmet", false},
+		{"Zakum", "Zakum Helmet", true},
+		{"Zakum", "ㅋ마ㅕㅡ", true},
+		{"rkskek", "가나다라", true},
+		{"rkskek", "가나라", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.search+" / "+tt.target, func(t *testing.T) {
+			m := MustNewMatcher(tt.search, WithEnglishKeyboard())
+			if got := m.MatchString(tt.target); got != tt.want {
+				t.Errorf("MatchString() got = %v, want %v, pattern %v", got, tt.want, m)
+			}
+		})
+	}
+}
+
+func TestHangulToKeys(t *testing.T) {
+	tests := []struct {
+		hangul string
+		want   string
+	}{
+		{"", ""},
+		{"가나다", "rkskek"},
+		{"아케인", "dkzpdls"},
+		{"ㅡㅔ5", "mp5"},
+		{"ㅡㅖ5", "mP5"},
+		{"와", "dhk"},
+		{"ﾡ", "r"},
+		{"ￂ", "k"},
+		{"ﾪ", "fr"},
+		{"ￎ", "ho"},
+		{"의", "dml"},
+		{"닭", "ekfr"},
+		{"ㄳ", "rt"},
+		{"까치", "Rkcl"},
+		{"ㅋ마ㅕㅡ", "zakum"},
+		{"Zakum 헬멧", "Zakum gpfapt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.hangul, func(t *testing.T) {
+			if got := HangulToKeys(tt.hangul); got != tt.want {
+				t.Errorf("HangulToKeys() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKoreanKeyboard(t *testing.T) {
+	tests := []struct {
+		search string
+		target string
+		want   bool
+	}{
+		{"ㅡㅔ5", "MP5 Rifle", true},
+		{"ㅡㅔ5", "mp5", true},
+		{"ㅡㅔ5", "ㅡㅔ5", true},
+		{"ㅋ마ㅕㅡ", "Zakum Helmet", true},
+		{"ㅋ마ㅕ", "Zakum Helmet", true},
+		{"ㅋ마ㅕㅡ", "Zakun", false},
+		{"ㅡㅔ5", "MP7", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.search+" / "+tt.target, func(t *testing.T) {
+			m := MustNewMatcher(tt.search, WithKoreanKeyboard())
+			if got := m.MatchString(tt.target); got != tt.want {
+				t.Errorf("MatchString() got = %v, want %v, pattern %v", got, tt.want, m)
+			}
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package hangul_regexp
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+	"unicode/utf8"
+)
+
+type Matcher struct {
+	search  string
+	opts    Options
+	pattern string
+	// Either regex or native is set, depending on the engine.
+	regex  *regexp.Regexp
+	native *nativeMatcher
+
+	highlightOnce  sync.Once
+	highlightRegex *regexp.Regexp
+	highlightAtoms []atom
+
+	// scoreNative scores matches of EngineRegexp, which only finds the
+	// leftmost one.
+	scoreOnce   sync.Once
+	scoreNative *nativeMatcher
+}
+
+func NewMatcher(search string, opts ...Option) (*Matcher, error) {
+	o := NewOptions(opts...)
+	if o.Engine == EngineNative {
+		if err := o.Validate(); err != nil {
+			return nil, fmt.Errorf("hangul_regexp: search %q: %w", search, err)
+		}
+		branches := buildBranches(search, o, true)
+		return &Matcher{search: search, opts: o, native: newNativeMatcher(branches, o)}, nil
+	}
+	regex, err := Compile(search, o)
+	if err != nil {
+		return nil, err
+	}
+	return &Matcher{search: search, opts: o, pattern: regex.String(), regex: regex}, nil
+}
+
+func MustNewMatcher(search string, opts ...Option) *Matcher {
+	m, err := NewMatcher(search, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+func (m *Matcher) Search() string {
+	return m.search
+}
+
+// Regexp returns the compiled pattern, or nil for EngineNative.
+func (m *Matcher) Regexp() *regexp.Regexp {
+	return m.regex
+}
+
+func (m *Matcher) String() string {
+	if m.native != nil {
+		pattern, _ := Pattern(m.search, m.opts)
+		return pattern
+	}
+	return m.pattern
+}
+
+// The matching methods also accept targets in NFD or with halfwidth jamo, and
+// report the offsets in the target as given.
+
+func (m *Matcher) MatchString(s string) bool {
+	s, _ = normalizeJamo(s)
+	if m.native != nil {
+		return m.native.MatchString(s)
+	}
+	return m.regex.MatchString(s)
+}
+
+func (m *Matcher) FindStringIndex(s string) []int {
+	t, sources := normalizeJamo(s)
+	loc := m.findStringIndex(t)
+	if loc != nil && sources != nil {
+		loc[0], loc[1] = sources[loc[0]], sources[loc[1]]
+	}
+	return loc
+}
+
+func (m *Matcher) findStringIndex(s string) []int {
+	if m.native != nil {
+		return m.native.FindStringIndex(s)
+	}
+	loc := m.regex.FindStringIndex(s)
+	if loc != nil && m.opts.Anchor == AnchorWordStart {
+		loc[0] = m.wordStart(s, loc[0])
+	}
+	return loc
+}
+
+func (m *Matcher) FindAllStringIndex(s string, n int) [][]int {
+	t, sources := normalizeJamo(s)
+	locs := m.findAllStringIndex(t, n)
+	if sources != nil {
+		for _, loc := range locs {
+			loc[0], loc[1] = sources[loc[0]], sources[loc[1]]
+		}
+	}
+	return locs
+}
+
+func (m *Matcher) findAllStringIndex(s string, n int) [][]int {
+	if m.native != nil {
+		return m.native.FindAllStringIndex(s, n)
+	}
+	locs := m.regex.FindAllStringIndex(s, n)
+	if m.opts.Anchor == AnchorWordStart {
+		for _, loc := range locs {
+			loc[0] = m.wordStart(s, loc[0])
+		}
+	}
+	return locs
+}
+
+// wordStart returns the start of the word matched by a match of the regexp at
+// start, which includes the character before the word unless it is at the
+// start of s.
+func (m *Matcher) wordStart(s string, start int) int {
+	ch, size := utf8.DecodeRuneInString(s[start:])
+	if start > 0 {
+		return start + size
+	}
+	if size == 0 || isWordRune(ch) {
+		return 0
+	}
+	// The match at the start of s consumed the non-word character only if
+	// the word does not start at 0, so look at where its first atom is.
+	if matches, _ := m.matched(s); len(matches) > 0 {
+		return matches[0].span.Start
+	}
+	return 0
+}
+
+// Filter returns the targets that match, keeping their order.
+func (m *Matcher) Filter(targets []string) []string {
+	var matched []string
+	for _, target := range targets {
+		if m.MatchString(target) {
+			matched = append(matched, target)
+		}
+	}
+	return matched
+}
+
+// Highlight returns the ranges of the leftmost match in the target for each
+// character of the search, or nil if the target does not match.
+func (m *Matcher) Highlight(target string) []CharHighlight {
+	t, sources := normalizeJamo(target)
+	matches, ok := m.matched(t)
+	if !ok {
+		return nil
+	}
+	remapMatches(matches, sources)
+	return collectHighlights(m.search, matches)
+}
+
+// matched returns the atoms of the leftmost match in the target, or false if
+// the target does not match.
+func (m *Matcher) matched(target string) ([]matchedAtom, bool) {
+	if m.native != nil {
+		return m.native.match(target)
+	}
+	m.highlightOnce.Do(func() {
+		o := m.opts
+		o.Capturing = true
+		var pattern string
+		pattern, m.highlightAtoms = buildPattern(m.search, o)
+		m.highlightRegex = regexp.MustCompile(pattern)
+	})
+	loc := m.highlightRegex.FindStringSubmatchIndex(target)
+	if loc == nil {
+		return nil, false
+	}
+	var matches []matchedAtom
+	for g, a := range m.highlightAtoms {
+		if start := loc[2*g+2]; start >= 0 {
+			matches = append(matches, matchedAtom{a, Span{start, loc[2*g+3]}})
+		}
+	}
+	return matches, true
+}
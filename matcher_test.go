@@ -0,0 +1,142 @@
+package hangul_regexp
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMatcher(t *testing.T) {
+	m, err := NewMatcher("ㅇㅋㅇ", WithChoseong(), WithFuzzy())
+	if err != nil {
+		t.Fatalf("NewMatcher() error = %v", err)
+	}
+
+	if !m.MatchString("아케인셰이드 스태프") {
+		t.Errorf("MatchString() got = false, want true")
+	}
+	if m.MatchString("아이스 스태프") {
+		t.Errorf("MatchString() got = true, want false")
+	}
+
+	if got, want := m.FindStringIndex("[아케인] 아케인"), []int{1, 10}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FindStringIndex() got = %v, want %v", got, want)
+	}
+	if got := m.FindStringIndex("abc"); got != nil {
+		t.Errorf("FindStringIndex() got = %v, want nil", got)
+	}
+
+	if got, want := m.FindAllStringIndex("아케인 오크완드", -1), [][]int{{0, 9}, {10, 19}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("FindAllStringIndex() got = %v, want %v", got, want)
+	}
+
+	targets := []string{"아케인셰이드 스태프", "아이스 스태프", "에테르넬 스태프", "오크 완드"}
+	if got, want := m.Filter(targets), []string{"아케인셰이드 스태프", "오크 완드"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Filter() got = %v, want %v", got, want)
+	}
+}
+
+func TestMatcher_Anchor(t *testing.T) {
+	tests := []struct {
+		name   string
+		search string
+		anchor Anchor
+		target string
+		want   [][]int
+	}{
+		{"None", "검", AnchorNone, "대검 검", [][]int{{3, 6}, {7, 10}}},
+		{"Prefix", "검", AnchorPrefix, "검 대검", [][]int{{0, 3}}},
+		{"Prefix / no match", "검", AnchorPrefix, "대검 검", nil},
+		{"Full", "대검", AnchorFull, "대검", [][]int{{0, 6}}},
+		{"Full / no match", "대검", AnchorFull, "대검 검", nil},
+		{"Word start", "검", AnchorWordStart, "대검 검(검)", [][]int{{7, 10}, {11, 14}}},
+		{"Word start / Hangul is a word character", "검", AnchorWordStart, "대검", nil},
+		{"Word start / at start", "대", AnchorWordStart, "대검 대", [][]int{{0, 3}, {7, 10}}},
+		{"Word start / search starting with non-word character", "(검", AnchorWordStart, "(검 대(검", [][]int{{0, 4}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, engine := range []Engine{EngineRegexp, EngineNative} {
+				m := MustNewMatcher(tt.search, WithAnchor(tt.anchor), WithEngine(engine))
+				if got := m.FindAllStringIndex(tt.target, -1); !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("FindAllStringIndex() got = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestNewMatcherError(t *testing.T) {
+	if _, err := NewMatcher("가", WithIgnoreSpace(), WithFuzzy()); err == nil {
+		t.Errorf("NewMatcher() error = nil, want error")
+	}
+}
+
+func TestNativeEngine(t *testing.T) {
+	searches := []string{
+		"", "a", "가", "ㄱ", "ㅇㅋㅇ", "마깃아", "마깃안", "이이저", "루컨ㅁ", "낢", "ㅄ", "ㄻㅄ", "가 안", "갈", "고", "갑ㅏ", "ㅂㅏ", "ㅏ",
+		"아케인셰이드 에너지소드", "ㅇㅋㅇㅅㅇㄷ ㅇㄴㅈㅅㄷ", "[^가-힣]$", "dkzpdls", "ㅡㅔ5", "gangnam", "ㄱㅏㄴ", "\u1106\u1161\u1100\u1175\u11ba\u110b\u1161", "\u1100", "ﾡﾤ", "ᄒᆞᆫ", "ᄒᆞᆫ글", "가치", "각다", "ㅅ", "바", "밥", "ㄱㅊ", "가안", "곡곡", "나곡", " 곡", "각각",
+	}
+	targets := []string{
+		"", "a", "가", "마력이 깃든 안대", "마력이 깃든 안대\n마깃안", "아케인셰이드 에너지소드", "아케인셰이드 스태프", "이글아이 레인저슈트",
+		"루즈 컨트롤 머신 마크", "날아 먹", "보라색", "가  아니", "갈비 갉다", "과자", "가바 가방", "아이", "MP5 Rifle", "강남역", "[^가-힣]$",
+		"ㄱ가ㄴ나", "아케인·셰이드", "아케인-_셰이드", "아케인\u3000셰이드", "가\u00a0안", "가\t\u200b아니", "aaa", "\u1106\u1161\u1105\u1167\u11a8\u110b\u1175 \u1100\u1175\u11ba\u1103\u1173\u11ab \u110b\u1161\u11ab\u1103\u1162", "\u1100 \u11a8", "ﾡﾤ 강남", "훈민정음 ᄒᆞᆫ글", "ᄒ ᆞ ᆫ", "ＭＰ５ Ｒｉｆｌｅ", "mp5 rifle", "가나 가나 가나", "가나-가나.가나", "[가나]", " 가", "-가-", "가가 가", "까치 깎다", "쌍ㅆ", "파도 타기", "가아나안", "곡ㄱ곡고곡", "나고곡각", " 고가곡", "가과각.가 갂", strings.Repeat("아 ", 200) + "아",
+	}
+	optionSets := [][]Option{
+		nil,
+		{WithIgnoreSpace()},
+		{WithFuzzy()},
+		{WithChoseong()},
+		{WithFuzzy(), WithChoseong()},
+		{WithIgnoreSpace(), WithChoseong()},
+		{WithRawJamo()},
+		{WithEnglishKeyboard(), WithFuzzy()},
+		{WithKoreanKeyboard()},
+		{WithRomanized(), WithIgnoreSpace()},
+		{WithGap(Gap{Chars: "·-_"})},
+		{WithGap(Gap{Whitespace: true, Chars: "-", Max: 1})},
+		{WithGap(Gap{Any: true, Max: 2}), WithChoseong()},
+		{WithFoldCaseAndWidth(), WithKoreanKeyboard()},
+		{WithFoldCaseAndWidth(), WithFuzzy()},
+		{WithAnchor(AnchorPrefix)},
+		{WithAnchor(AnchorFull), WithFuzzy()},
+		{WithAnchor(AnchorWordStart)},
+		{WithAnchor(AnchorWordStart), WithChoseong(), WithFuzzy()},
+		{WithAnchor(AnchorWordStart), WithEnglishKeyboard()},
+		{WithRelaxTense()},
+		{WithRelaxTense(), WithRelaxAspirated(), WithChoseong(), WithFuzzy()},
+	}
+	for _, opts := range optionSets {
+		for _, search := range searches {
+			regex := MustNewMatcher(search, opts...)
+			native := MustNewMatcher(search, append(opts, WithEngine(EngineNative))...)
+			if native.Regexp() != nil {
+				t.Fatalf("Regexp() got = %v, want nil", native.Regexp())
+			}
+			if native.String() != regex.String() {
+				t.Errorf("String() got = %v, want %v", native.String(), regex.String())
+			}
+			for _, target := range targets {
+				if got, want := native.MatchString(target), regex.MatchString(target); got != want {
+					t.Errorf("%v / %q: MatchString() got = %v, want %v", regex, target, got, want)
+				}
+				if got, want := native.FindStringIndex(target), regex.FindStringIndex(target); !reflect.DeepEqual(got, want) {
+					t.Errorf("%v / %q: FindStringIndex() got = %v, want %v", regex, target, got, want)
+				}
+				if got, want := native.FindAllStringIndex(target, -1), regex.FindAllStringIndex(target, -1); !reflect.DeepEqual(got, want) {
+					t.Errorf("%v / %q: FindAllStringIndex() got = %v, want %v", regex, target, got, want)
+				}
+				if got, want := native.FindAllStringIndex(target, 1), regex.FindAllStringIndex(target, 1); !reflect.DeepEqual(got, want) {
+					t.Errorf("%v / %q: FindAllStringIndex(1) got = %v, want %v", regex, target, got, want)
+				}
+				if got, want := native.Highlight(target), regex.Highlight(target); !reflect.DeepEqual(got, want) {
+					t.Errorf("%v / %q: Highlight() got = %v, want %v", regex, target, got, want)
+				}
+				gotScore, _ := native.Score(target)
+				if wantScore, _ := regex.Score(target); gotScore != wantScore {
+					t.Errorf("%v / %q: Score() got = %v, want %v", regex, target, gotScore, wantScore)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,393 @@
+package hangul_regexp
+
+import (
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// nativeMatcher matches the branches of a search directly over the runes of
+// the target. It explores the alternatives in the order of the pattern, and
+// skips as few runes as possible at connectors, so it finds the same matches
+// as the leftmost-first regexp engine.
+type nativeMatcher struct {
+	branches [][]nativeSegment
+	skip     func(rune) bool
+	// maxGap limits the runes skipped by a connector if positive.
+	maxGap int
+	anchor Anchor
+	// states is the number of atoms, which are numbered in pattern order.
+	states int
+	// first matches the runes that can start a match, or is nil if a match
+	// can be empty.
+	first *atom
+	// minLen is the least number of bytes of a match.
+	minLen   int
+	memoPool sync.Pool
+}
+
+type nativeSegment [][]nativeAtom
+
+type nativeAtom struct {
+	atom
+	state int
+	// lit is the UTF-8 encoding of the rune if the atom matches a single one.
+	lit string
+}
+
+func newNativeMatcher(branches []branch, o Options) *nativeMatcher {
+	m := &nativeMatcher{skip: o.skip(), maxGap: o.gap().Max, anchor: o.Anchor, first: &atom{}, minLen: -1}
+	m.branches = make([][]nativeSegment, len(branches))
+	for b, br := range branches {
+		if n := minMatchLen(br.segments); m.minLen < 0 || n < m.minLen {
+			m.minLen = n
+		}
+		if len(br.segments) == 0 {
+			m.first = nil
+		} else if m.first != nil {
+			for _, alt := range br.segments[0].alts {
+				m.first.lits = append(m.first.lits, alt[0].lits...)
+				m.first.ranges = append(m.first.ranges, alt[0].ranges...)
+			}
+		}
+		m.branches[b] = make([]nativeSegment, len(br.segments))
+		for i, seg := range br.segments {
+			m.branches[b][i] = make(nativeSegment, len(seg.alts))
+			for a, alt := range seg.alts {
+				m.branches[b][i][a] = make([]nativeAtom, len(alt))
+				for j := range alt {
+					m.branches[b][i][a][j] = nativeAtom{atom: alt[j], state: m.states}
+					if len(alt[j].lits) == 1 && len(alt[j].ranges) == 0 {
+						m.branches[b][i][a][j].lit = string(alt[j].lits[0])
+					}
+					m.states++
+				}
+			}
+		}
+	}
+	return m
+}
+
+func minMatchLen(segments []segment) int {
+	n := 0
+	for _, seg := range segments {
+		segLen := -1
+		for _, alt := range seg.alts {
+			altLen := 0
+			for _, a := range alt {
+				altLen += a.minLen()
+			}
+			if segLen < 0 || altLen < segLen {
+				segLen = altLen
+			}
+		}
+		n += segLen
+	}
+	return n
+}
+
+// minLen returns the least number of bytes of a rune matched by the atom.
+func (a *atom) minLen() int {
+	n := utf8.UTFMax
+	for _, lit := range a.lits {
+		n = min(n, utf8.RuneLen(lit))
+	}
+	for _, r := range a.ranges {
+		n = min(n, utf8.RuneLen(r.lo))
+	}
+	return n
+}
+
+func (a *atom) matches(ch rune) bool {
+	for _, lit := range a.lits {
+		if lit == ch {
+			return true
+		}
+	}
+	for _, r := range a.ranges {
+		if r.lo <= ch && ch <= r.hi {
+			return true
+		}
+	}
+	return false
+}
+
+// nativeRun holds the state of matching a single target.
+type nativeRun struct {
+	m *nativeMatcher
+	s string
+	// failed marks the states and positions before the connector that are
+	// known not to lead to a match. It is allocated once failures exceed the
+	// length of the target, as most targets fail without backtracking much.
+	failed   *[]uint64
+	failures int
+	// path holds the atoms of the current match when tracked.
+	path      []matchedAtom
+	trackPath bool
+}
+
+func (r *nativeRun) markFailed(i int) {
+	if r.failed == nil {
+		if r.failures++; r.failures <= len(r.s) {
+			return
+		}
+		n := (r.m.states*(len(r.s)+1) + 63) / 64
+		if pooled, ok := r.m.memoPool.Get().(*[]uint64); ok && cap(*pooled) >= n {
+			*pooled = (*pooled)[:n]
+			clear(*pooled)
+			r.failed = pooled
+		} else {
+			failed := make([]uint64, n)
+			r.failed = &failed
+		}
+	}
+	(*r.failed)[i/64] |= 1 << (i % 64)
+}
+
+func (r *nativeRun) isFailed(i int) bool {
+	return r.failed != nil && (*r.failed)[i/64]&(1<<(i%64)) != 0
+}
+
+func (r *nativeRun) release() {
+	if r.failed != nil {
+		r.m.memoPool.Put(r.failed)
+	}
+}
+
+// find returns the leftmost match starting at or after from. Empty matches at
+// notEmptyAt are skipped.
+func (r *nativeRun) find(from int, notEmptyAt int) (int, int, bool) {
+	for start := from; start <= len(r.s)-r.m.minLen; {
+		if first := r.m.first; first != nil {
+			if len(first.ranges) == 0 && len(first.lits) == 1 {
+				i := strings.IndexRune(r.s[start:], first.lits[0])
+				if i < 0 {
+					break
+				}
+				start += i
+			} else if ch, size := utf8.DecodeRuneInString(r.s[start:]); size == 0 {
+				break
+			} else if !first.matches(ch) {
+				start += size
+				continue
+			}
+		}
+		if !r.canStart(from, start) {
+			if r.m.anchor == AnchorPrefix || r.m.anchor == AnchorFull || start == len(r.s) {
+				break
+			}
+			_, size := utf8.DecodeRuneInString(r.s[start:])
+			start += size
+			continue
+		}
+		for _, segs := range r.m.branches {
+			if end, ok := r.matchBranch(segs, start); ok && (!r.empty(start, end) || start != notEmptyAt) {
+				return start, end, true
+			}
+			r.path = r.path[:0]
+		}
+		if start == len(r.s) {
+			break
+		}
+		_, size := utf8.DecodeRuneInString(r.s[start:])
+		start += size
+	}
+	return 0, 0, false
+}
+
+// empty reports whether a match is empty in the pattern, which consumes the
+// character before a word.
+func (r *nativeRun) empty(start, end int) bool {
+	return start == end && (r.m.anchor != AnchorWordStart || start == 0)
+}
+
+// canStart reports whether a match can start at start when searching from
+// from. As the pattern consumes the character before a word, it must not be
+// before from.
+func (r *nativeRun) canStart(from, start int) bool {
+	switch r.m.anchor {
+	case AnchorPrefix, AnchorFull:
+		return start == 0
+	case AnchorWordStart:
+		if start == 0 {
+			return true
+		}
+		prev, size := utf8.DecodeLastRuneInString(r.s[:start])
+		return start-size >= from && !isWordRune(prev)
+	}
+	return true
+}
+
+func (r *nativeRun) matchBranch(segs []nativeSegment, start int) (int, bool) {
+	if len(segs) == 0 {
+		return start, r.m.anchor != AnchorFull || start == len(r.s)
+	}
+	for _, alt := range segs[0] {
+		if end, ok := r.matchAtoms(segs, 0, alt, 0, start, true); ok {
+			return end, true
+		}
+	}
+	return 0, false
+}
+
+// matchAtoms matches the atoms of alt from j, and then the segments following
+// segs[i], at pos. The connector before the atom is skipped unless first or
+// the atom is joined.
+func (r *nativeRun) matchAtoms(segs []nativeSegment, i int, alt []nativeAtom, j int, pos int, first bool) (int, bool) {
+	if j == len(alt) {
+		if i+1 == len(segs) {
+			return pos, r.m.anchor != AnchorFull || pos == len(r.s)
+		}
+		return r.matchSegment(segs, i+1, pos)
+	}
+
+	a := &alt[j]
+	first = first || a.joined
+	memo := -1
+	if !first && r.m.skip != nil {
+		memo = a.state*(len(r.s)+1) + pos
+		if r.isFailed(memo) {
+			return 0, false
+		}
+	}
+	for p, skipped := pos, 0; p < len(r.s); skipped++ {
+		var matched bool
+		var ch rune
+		var size int
+		if a.lit != "" && strings.HasPrefix(r.s[p:], a.lit) {
+			// The rune is decoded only if the connector needs to skip it.
+			matched, ch, size = true, -1, len(a.lit)
+		} else {
+			ch, size = utf8.DecodeRuneInString(r.s[p:])
+			matched = a.lit == "" && a.matches(ch)
+		}
+		if matched {
+			n := len(r.path)
+			if r.trackPath {
+				r.path = append(r.path, matchedAtom{a.atom, Span{p, p + size}})
+			}
+			if end, ok := r.matchAtoms(segs, i, alt, j+1, p+size, false); ok {
+				return end, true
+			}
+			r.path = r.path[:n]
+		}
+		if first || r.m.skip == nil {
+			break
+		}
+		if ch < 0 {
+			ch, _ = utf8.DecodeRuneInString(r.s[p:])
+		}
+		if !r.m.skip(ch) || r.m.maxGap > 0 && skipped == r.m.maxGap {
+			break
+		}
+		p += size
+	}
+	if memo >= 0 {
+		r.markFailed(memo)
+	}
+	return 0, false
+}
+
+// matchSegment matches an alternative of segs[i], and then the segments
+// following it, after the connector at pos. As the pattern writes the
+// connector before the alternation, every alternative is tried before the
+// connector skips another rune.
+func (r *nativeRun) matchSegment(segs []nativeSegment, i int, pos int) (int, bool) {
+	if len(segs[i]) == 1 {
+		return r.matchAtoms(segs, i, segs[i][0], 0, pos, false)
+	}
+	memo := -1
+	if r.m.skip != nil {
+		memo = segs[i][0][0].state*(len(r.s)+1) + pos
+		if r.isFailed(memo) {
+			return 0, false
+		}
+	}
+	for p, skipped := pos, 0; ; skipped++ {
+		for _, alt := range segs[i] {
+			if end, ok := r.matchAtoms(segs, i, alt, 0, p, true); ok {
+				return end, true
+			}
+		}
+		if r.m.skip == nil || p == len(r.s) {
+			break
+		}
+		ch, size := utf8.DecodeRuneInString(r.s[p:])
+		if !r.m.skip(ch) || r.m.maxGap > 0 && skipped == r.m.maxGap {
+			break
+		}
+		p += size
+	}
+	if memo >= 0 {
+		r.markFailed(memo)
+	}
+	return 0, false
+}
+
+func (m *nativeMatcher) MatchString(s string) bool {
+	r := nativeRun{m: m, s: s}
+	_, _, ok := r.find(0, -1)
+	r.release()
+	return ok
+}
+
+func (m *nativeMatcher) FindStringIndex(s string) []int {
+	r := nativeRun{m: m, s: s}
+	defer r.release()
+	start, end, ok := r.find(0, -1)
+	if !ok {
+		return nil
+	}
+	return []int{start, end}
+}
+
+func (m *nativeMatcher) FindAllStringIndex(s string, n int) [][]int {
+	r := nativeRun{m: m, s: s}
+	defer r.release()
+	var matches [][]int
+	for from, prevEnd := 0, -1; n < 0 || len(matches) < n; {
+		start, end, ok := r.find(from, prevEnd)
+		if !ok {
+			break
+		}
+		matches = append(matches, []int{start, end})
+		prevEnd = end
+		if !r.empty(start, end) {
+			from = end
+		} else if end < len(s) {
+			_, size := utf8.DecodeRuneInString(s[end:])
+			from = end + size
+		} else {
+			break
+		}
+	}
+	return matches
+}
+
+// eachMatch calls f with the atoms of the leftmost-first match starting at
+// each position of s where a match starts.
+func (m *nativeMatcher) eachMatch(s string, f func([]matchedAtom)) {
+	r := nativeRun{m: m, s: s, trackPath: true}
+	defer r.release()
+	for from := 0; from <= len(s); {
+		start, _, ok := r.find(from, -1)
+		if !ok {
+			break
+		}
+		f(r.path)
+		r.path = r.path[:0]
+		if start == len(s) {
+			break
+		}
+		_, size := utf8.DecodeRuneInString(s[start:])
+		from = start + size
+	}
+}
+
+// match returns the atoms of the leftmost match, or false if there is none.
+func (m *nativeMatcher) match(s string) ([]matchedAtom, bool) {
+	r := nativeRun{m: m, s: s, trackPath: true}
+	defer r.release()
+	_, _, ok := r.find(0, -1)
+	return r.path, ok
+}
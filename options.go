@@ -0,0 +1,282 @@
+package hangul_regexp
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type Engine int
+
+const (
+	// EngineRegexp matches with the compiled pattern.
+	EngineRegexp Engine = iota
+	// EngineNative matches by interpreting the search directly, which avoids
+	// compiling a regexp. It produces the same matches as EngineRegexp.
+	EngineNative
+)
+
+type Anchor int
+
+const (
+	// AnchorNone matches anywhere in the target.
+	AnchorNone Anchor = iota
+	// AnchorPrefix matches at the start of the target.
+	AnchorPrefix
+	// AnchorFull matches the whole target.
+	AnchorFull
+	// AnchorWordStart matches at the start of a word, which is a run of
+	// letters, including Hangul and jamo, and digits. The pattern consumes the
+	// character before the word, but Matcher reports the match without it.
+	AnchorWordStart
+)
+
+var (
+	ErrIgnoreSpaceAndFuzzy = errors.New("ignoreSpace and fuzzy cannot be true at the same time")
+	ErrGapMaxOutOfRange    = errors.New("gap max must be between 0 and 1000")
+	ErrUnknownEngine       = errors.New("unknown engine")
+	ErrUnknownAnchor       = errors.New("unknown anchor")
+)
+
+// maxGapMax is the largest repeat count allowed by regexp.
+const maxGapMax = 1000
+
+// Gap configures the characters that can be skipped between the characters of
+// the search. IgnoreSpace sets Whitespace and ZeroWidth, and Fuzzy sets Any.
+type Gap struct {
+	// Whitespace skips any Unicode whitespace.
+	Whitespace bool
+	// ZeroWidth skips zero-width spaces, joiners and the byte order mark.
+	ZeroWidth bool
+	// Chars holds other characters to skip, such as "·-_".
+	Chars string
+	// Any skips any character except a newline.
+	Any bool
+	// Max limits the number of characters skipped by a gap if positive.
+	Max int
+}
+
+type Options struct {
+	// IgnoreSpace skips Unicode whitespace and zero-width characters between
+	// the characters of the search, and ignores the whitespace of the search.
+	IgnoreSpace   bool
+	Fuzzy         bool
+	MatchChoseong bool
+	Capturing     bool
+	// RawJamo assembles a search of compatibility jamo, such as "ㄱㅏㄴ",
+	// into syllables before generating the pattern.
+	RawJamo bool
+	// EnglishKeyboard also matches the search as if it was typed on a
+	// 2-beolsik keyboard in English mode, so "dkzpdls" matches "아케인".
+	EnglishKeyboard bool
+	// KoreanKeyboard also matches Latin text as if it was typed on a
+	// 2-beolsik keyboard in Korean mode, so "ㅡㅔ5" matches "MP5".
+	KoreanKeyboard bool
+	// Romanized also reads Latin letters in the search as romanized Korean,
+	// so "gangnam" matches "강남".
+	Romanized bool
+	// Engine is the matching engine used by Matcher.
+	Engine Engine
+	Anchor Anchor
+	Gap    Gap
+	// FoldCaseAndWidth matches ASCII characters of the search ignoring case,
+	// and as both their halfwidth and fullwidth forms (U+FF01 to U+FF5E).
+	FoldCaseAndWidth bool
+	// RelaxTense also matches the plain consonants ㄱ, ㄷ, ㅂ, ㅅ and ㅈ of the
+	// search, as choseong or batchim, as their tense counterparts, so "가" and
+	// "ㅅ" match "까" and "ㅆ".
+	RelaxTense bool
+	// RelaxAspirated likewise matches ㄱ, ㄷ, ㅂ and ㅈ as ㅋ, ㅌ, ㅍ and ㅊ.
+	RelaxAspirated bool
+}
+
+// Option configures pattern generation. Both Options values and the With*
+// functions implement it, so they can be mixed in a single call.
+type Option interface {
+	apply(*Options)
+}
+
+type optionFunc func(*Options)
+
+func (f optionFunc) apply(o *Options) {
+	f(o)
+}
+
+func (o Options) apply(dst *Options) {
+	*dst = o
+}
+
+func WithIgnoreSpace() Option {
+	return optionFunc(func(o *Options) {
+		o.IgnoreSpace = true
+	})
+}
+
+func WithFuzzy() Option {
+	return optionFunc(func(o *Options) {
+		o.Fuzzy = true
+	})
+}
+
+func WithChoseong() Option {
+	return optionFunc(func(o *Options) {
+		o.MatchChoseong = true
+	})
+}
+
+func WithCapturing() Option {
+	return optionFunc(func(o *Options) {
+		o.Capturing = true
+	})
+}
+
+func WithRawJamo() Option {
+	return optionFunc(func(o *Options) {
+		o.RawJamo = true
+	})
+}
+
+func WithEnglishKeyboard() Option {
+	return optionFunc(func(o *Options) {
+		o.EnglishKeyboard = true
+	})
+}
+
+func WithKoreanKeyboard() Option {
+	return optionFunc(func(o *Options) {
+		o.KoreanKeyboard = true
+	})
+}
+
+func WithRomanized() Option {
+	return optionFunc(func(o *Options) {
+		o.Romanized = true
+	})
+}
+
+func WithEngine(engine Engine) Option {
+	return optionFunc(func(o *Options) {
+		o.Engine = engine
+	})
+}
+
+func WithAnchor(anchor Anchor) Option {
+	return optionFunc(func(o *Options) {
+		o.Anchor = anchor
+	})
+}
+
+func WithGap(gap Gap) Option {
+	return optionFunc(func(o *Options) {
+		o.Gap = gap
+	})
+}
+
+func WithFoldCaseAndWidth() Option {
+	return optionFunc(func(o *Options) {
+		o.FoldCaseAndWidth = true
+	})
+}
+
+func WithRelaxTense() Option {
+	return optionFunc(func(o *Options) {
+		o.RelaxTense = true
+	})
+}
+
+func WithRelaxAspirated() Option {
+	return optionFunc(func(o *Options) {
+		o.RelaxAspirated = true
+	})
+}
+
+func NewOptions(opts ...Option) Options {
+	o := Options{}
+	for _, opt := range opts {
+		opt.apply(&o)
+	}
+	return o
+}
+
+func (o Options) Validate() error {
+	if o.IgnoreSpace && o.Fuzzy {
+		return ErrIgnoreSpaceAndFuzzy
+	}
+	if o.Gap.Max < 0 || o.Gap.Max > maxGapMax {
+		return ErrGapMaxOutOfRange
+	}
+	if o.Engine != EngineRegexp && o.Engine != EngineNative {
+		return ErrUnknownEngine
+	}
+	if o.Anchor < AnchorNone || o.Anchor > AnchorWordStart {
+		return ErrUnknownAnchor
+	}
+	return nil
+}
+
+func (o Options) gap() Gap {
+	g := o.Gap
+	if o.IgnoreSpace {
+		g.Whitespace = true
+		g.ZeroWidth = true
+	}
+	if o.Fuzzy {
+		g.Any = true
+	}
+	return g
+}
+
+func (o Options) connector() string {
+	g := o.gap()
+	if !g.Any && !g.Whitespace && !g.ZeroWidth && g.Chars == "" {
+		return ""
+	}
+	builder := strings.Builder{}
+	if g.Any {
+		builder.WriteRune('.')
+	} else if ch, size := utf8.DecodeRuneInString(g.Chars); !g.Whitespace && !g.ZeroWidth && size == len(g.Chars) {
+		writeEscaped(&builder, ch)
+	} else {
+		builder.WriteRune('[')
+		if g.Whitespace {
+			// The whitespace of unicode.IsSpace, as \s only matches ASCII.
+			builder.WriteString(`\t-\r\x{85}\p{Z}`)
+		}
+		if g.ZeroWidth {
+			builder.WriteString(`\x{200B}-\x{200D}\x{2060}\x{FEFF}`)
+		}
+		for _, ch := range g.Chars {
+			writeClassEscaped(&builder, ch)
+		}
+		builder.WriteRune(']')
+	}
+	if g.Max > 0 {
+		builder.WriteString("{0," + strconv.Itoa(g.Max) + "}?")
+	} else {
+		builder.WriteString("*?")
+	}
+	return builder.String()
+}
+
+// skip reports whether the connector can skip the rune, or is nil if the
+// connector is empty.
+func (o Options) skip() func(rune) bool {
+	g := o.gap()
+	if g.Any {
+		return func(ch rune) bool {
+			return ch != '\n'
+		}
+	}
+	if !g.Whitespace && !g.ZeroWidth && g.Chars == "" {
+		return nil
+	}
+	return func(ch rune) bool {
+		return g.Whitespace && unicode.IsSpace(ch) || g.ZeroWidth && isZeroWidth(ch) || strings.ContainsRune(g.Chars, ch)
+	}
+}
+
+func isZeroWidth(ch rune) bool {
+	return '\u200B' <= ch && ch <= '\u200D' || ch == '\u2060' || ch == '\uFEFF'
+}
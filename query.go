@@ -0,0 +1,372 @@
+package hangul_regexp
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	ErrUnterminatedQuote = errors.New("unterminated quote")
+	ErrEmptyQueryTerm    = errors.New("empty term")
+)
+
+// Query matches targets against a tree of terms. Only the term ending the
+// search is completed as a syllable being typed.
+type Query struct {
+	search string
+	root   QueryNode
+}
+
+// QueryNode is a node of a query: *QueryTerm, *QueryAnd, *QueryOr or
+// *QueryNot.
+type QueryNode interface {
+	fmt.Stringer
+	matchString(s string) bool
+	appendHighlights(highlights []TermHighlight, search, target string, sources []int) []TermHighlight
+}
+
+// QueryTerm matches targets containing its text. Start and End are the byte
+// offsets of the text in the search, and Phrase is set if it was quoted.
+type QueryTerm struct {
+	Text       string
+	Start, End int
+	Phrase     bool
+	m          *nativeMatcher
+}
+
+// QueryAnd matches targets matching all of its nodes.
+type QueryAnd struct {
+	Nodes []QueryNode
+}
+
+// QueryOr matches targets matching any of its nodes.
+type QueryOr struct {
+	Nodes []QueryNode
+}
+
+// QueryNot matches targets not matching its node.
+type QueryNot struct {
+	Node QueryNode
+}
+
+// TermHighlight holds the highlights of the term at the byte offsets Start to
+// End of the search. The offsets of Chars also refer to the search.
+type TermHighlight struct {
+	Start, End int
+	Chars      []CharHighlight
+}
+
+// NewQuery returns a Query matching targets containing every whitespace
+// separated term of the search, in any order. The last term is completed only
+// if the search does not end with whitespace. The Engine option is ignored.
+func NewQuery(search string, opts ...Option) (*Query, error) {
+	o := NewOptions(opts...)
+	if err := o.Validate(); err != nil {
+		return nil, fmt.Errorf("hangul_regexp: search %q: %w", search, err)
+	}
+	root := &QueryAnd{}
+	for _, span := range splitTerms(search) {
+		root.Nodes = append(root.Nodes, newQueryTerm(search, span.Start, span.End, false, o))
+	}
+	return &Query{search: search, root: root}, nil
+}
+
+func MustNewQuery(search string, opts ...Option) *Query {
+	q, err := NewQuery(search, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+// ParseQuery returns a Query for a search in the query syntax. Terms separated
+// by whitespace must all match, in any order. A term is a run of characters
+// other than whitespace, '"' and '|', or a phrase in double quotes, which
+// keeps its whitespace. Terms joined by '|' match if any of them does, and a
+// leading '-' excludes targets matching the terms that follow it, so
+//
+//	아케인 -스태프 "셰이드 소드" 검|도
+//
+// matches targets containing 아케인, "셰이드 소드", and either 검 or 도, but not
+// 스태프. The Engine option is ignored.
+func ParseQuery(search string, opts ...Option) (*Query, error) {
+	o := NewOptions(opts...)
+	if err := o.Validate(); err != nil {
+		return nil, fmt.Errorf("hangul_regexp: search %q: %w", search, err)
+	}
+	p := queryParser{search: search, o: o}
+	root, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	return &Query{search: search, root: root}, nil
+}
+
+func MustParseQuery(search string, opts ...Option) *Query {
+	q, err := ParseQuery(search, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+func newQueryTerm(search string, start, end int, phrase bool, o Options) *QueryTerm {
+	text := search[start:end]
+	branches := buildBranches(text, o, end == len(search))
+	return &QueryTerm{Text: text, Start: start, End: end, Phrase: phrase, m: newNativeMatcher(branches, o)}
+}
+
+// splitTerms returns the spans of the runs of non-whitespace in the search.
+func splitTerms(search string) []Span {
+	var spans []Span
+	start := -1
+	for i, ch := range search {
+		if unicode.IsSpace(ch) {
+			if start >= 0 {
+				spans = append(spans, Span{start, i})
+				start = -1
+			}
+		} else if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, Span{start, len(search)})
+	}
+	return spans
+}
+
+type queryParser struct {
+	search string
+	pos    int
+	o      Options
+}
+
+func (p *queryParser) errorf(pos int, err error) error {
+	return fmt.Errorf("hangul_regexp: search %q: offset %d: %w", p.search, pos, err)
+}
+
+func (p *queryParser) peek() rune {
+	ch, _ := utf8.DecodeRuneInString(p.search[p.pos:])
+	return ch
+}
+
+func (p *queryParser) skipSpace() {
+	for p.pos < len(p.search) {
+		ch, size := utf8.DecodeRuneInString(p.search[p.pos:])
+		if !unicode.IsSpace(ch) {
+			break
+		}
+		p.pos += size
+	}
+}
+
+func (p *queryParser) parseAnd() (QueryNode, error) {
+	and := &QueryAnd{}
+	for p.skipSpace(); p.pos < len(p.search); p.skipSpace() {
+		node, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		and.Nodes = append(and.Nodes, node)
+	}
+	return and, nil
+}
+
+func (p *queryParser) parseUnary() (QueryNode, error) {
+	if p.peek() != '-' {
+		return p.parseOr()
+	}
+	p.pos++
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	return &QueryNot{node}, nil
+}
+
+func (p *queryParser) parseOr() (QueryNode, error) {
+	term, err := p.parseTerm()
+	if err != nil {
+		return nil, err
+	}
+	or := &QueryOr{Nodes: []QueryNode{term}}
+	for {
+		pos := p.pos
+		p.skipSpace()
+		if p.peek() != '|' {
+			p.pos = pos
+			break
+		}
+		p.pos++
+		p.skipSpace()
+		term, err := p.parseTerm()
+		if err != nil {
+			return nil, err
+		}
+		or.Nodes = append(or.Nodes, term)
+	}
+	if len(or.Nodes) == 1 {
+		return term, nil
+	}
+	return or, nil
+}
+
+func (p *queryParser) parseTerm() (QueryNode, error) {
+	start := p.pos
+	if p.peek() == '"' {
+		end := strings.IndexByte(p.search[start+1:], '"')
+		if end < 0 {
+			return nil, p.errorf(start, ErrUnterminatedQuote)
+		}
+		end += start + 1
+		if end == start+1 {
+			return nil, p.errorf(start, ErrEmptyQueryTerm)
+		}
+		p.pos = end + 1
+		return newQueryTerm(p.search, start+1, end, true, p.o), nil
+	}
+	for p.pos < len(p.search) {
+		ch, size := utf8.DecodeRuneInString(p.search[p.pos:])
+		if unicode.IsSpace(ch) || ch == '"' || ch == '|' {
+			break
+		}
+		p.pos += size
+	}
+	if p.pos == start {
+		return nil, p.errorf(start, ErrEmptyQueryTerm)
+	}
+	return newQueryTerm(p.search, start, p.pos, false, p.o), nil
+}
+
+func (q *Query) Search() string {
+	return q.search
+}
+
+// Root returns the root node of the query.
+func (q *Query) Root() QueryNode {
+	return q.root
+}
+
+func (q *Query) String() string {
+	return q.root.String()
+}
+
+// MatchString reports whether s matches. Like Matcher, Query accepts targets
+// in NFD or with halfwidth jamo.
+func (q *Query) MatchString(s string) bool {
+	s, _ = normalizeJamo(s)
+	return q.root.matchString(s)
+}
+
+// Filter returns the targets that match, keeping their order.
+func (q *Query) Filter(targets []string) []string {
+	var matched []string
+	for _, target := range targets {
+		if q.MatchString(target) {
+			matched = append(matched, target)
+		}
+	}
+	return matched
+}
+
+// Highlight returns the highlights of the leftmost match of each term that
+// matched the target, or nil if the target does not match. Excluded terms
+// and terms of an OR after the first matching one are not highlighted.
+func (q *Query) Highlight(target string) []TermHighlight {
+	t, sources := normalizeJamo(target)
+	if !q.root.matchString(t) {
+		return nil
+	}
+	return q.root.appendHighlights([]TermHighlight{}, q.search, t, sources)
+}
+
+func (t *QueryTerm) String() string {
+	if t.Phrase {
+		return `"` + t.Text + `"`
+	}
+	return t.Text
+}
+
+func (t *QueryTerm) matchString(s string) bool {
+	return t.m.MatchString(s)
+}
+
+func (t *QueryTerm) appendHighlights(highlights []TermHighlight, search, target string, sources []int) []TermHighlight {
+	matches, ok := t.m.match(target)
+	if !ok {
+		return highlights
+	}
+	remapMatches(matches, sources)
+	chars := collectHighlights(search[t.Start:t.End], matches)
+	for i := range chars {
+		chars[i].Start += t.Start
+		chars[i].End += t.Start
+	}
+	return append(highlights, TermHighlight{Start: t.Start, End: t.End, Chars: chars})
+}
+
+func (a *QueryAnd) String() string {
+	return "(" + joinNodes(a.Nodes, " ") + ")"
+}
+
+func (a *QueryAnd) matchString(s string) bool {
+	for _, node := range a.Nodes {
+		if !node.matchString(s) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a *QueryAnd) appendHighlights(highlights []TermHighlight, search, target string, sources []int) []TermHighlight {
+	for _, node := range a.Nodes {
+		highlights = node.appendHighlights(highlights, search, target, sources)
+	}
+	return highlights
+}
+
+func (o *QueryOr) String() string {
+	return "(" + joinNodes(o.Nodes, "|") + ")"
+}
+
+func (o *QueryOr) matchString(s string) bool {
+	for _, node := range o.Nodes {
+		if node.matchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *QueryOr) appendHighlights(highlights []TermHighlight, search, target string, sources []int) []TermHighlight {
+	for _, node := range o.Nodes {
+		if node.matchString(target) {
+			return node.appendHighlights(highlights, search, target, sources)
+		}
+	}
+	return highlights
+}
+
+func (n *QueryNot) String() string {
+	return "-" + n.Node.String()
+}
+
+func (n *QueryNot) matchString(s string) bool {
+	return !n.Node.matchString(s)
+}
+
+func (n *QueryNot) appendHighlights(highlights []TermHighlight, _, _ string, _ []int) []TermHighlight {
+	return highlights
+}
+
+func joinNodes(nodes []QueryNode, sep string) string {
+	strs := make([]string, len(nodes))
+	for i, node := range nodes {
+		strs[i] = node.String()
+	}
+	return strings.Join(strs, sep)
+}
@@ -0,0 +1,126 @@
+package hangul_regexp
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestQuery(t *testing.T) {
+	targets := []string{"에너지소드", "아케인셰이드 에너지소드", "에너지 스태프", "소드 오브 에너지", "소드"}
+	tests := []struct {
+		name   string
+		search string
+		opts   []Option
+		want   []string
+	}{
+		{"Any order", "소드 에너지", nil, []string{"에너지소드", "아케인셰이드 에너지소드", "소드 오브 에너지"}},
+		{"Last term completed", "소드 에넞", nil, []string{"에너지소드", "아케인셰이드 에너지소드", "소드 오브 에너지"}},
+		{"Other terms not completed", "에넞 소드", nil, nil},
+		{"Trailing space ends last term", "소드 에넞 ", nil, nil},
+		{"Choseong", "ㅅㄷ ㅇㄴㅈ", []Option{WithChoseong()}, []string{"에너지소드", "아케인셰이드 에너지소드", "소드 오브 에너지"}},
+		{"Single term", "소", nil, []string{"에너지소드", "아케인셰이드 에너지소드", "소드 오브 에너지", "소드"}},
+		{"Empty", " ", nil, targets},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := NewQuery(tt.search, tt.opts...)
+			if err != nil {
+				t.Fatalf("NewQuery() error = %v", err)
+			}
+			if got := q.Filter(targets); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Filter() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := NewQuery("가", WithIgnoreSpace(), WithFuzzy()); err == nil {
+		t.Errorf("NewQuery() error = nil, want error")
+	}
+}
+
+func TestQuery_Highlight(t *testing.T) {
+	q := MustNewQuery("소드 에너")
+	want := []TermHighlight{
+		{0, 6, []CharHighlight{{0, 3, []Span{{9, 12}}}, {3, 6, []Span{{12, 15}}}}},
+		{7, 13, []CharHighlight{{7, 10, []Span{{0, 3}}}, {10, 13, []Span{{3, 6}}}}},
+	}
+	if got := q.Highlight("에너지소드"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Highlight() got = %v, want %v", got, want)
+	}
+	if got := q.Highlight("에너지"); got != nil {
+		t.Errorf("Highlight() got = %v, want nil", got)
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	targets := []string{"아케인셰이드 스태프", "아케인셰이드 투핸드소드", "아케인셰이드 검", "아케인셰이드 도", "제네시스 도"}
+	tests := []struct {
+		name   string
+		search string
+		want   string
+		wantIn []string
+	}{
+		{"Terms", "아케인 도", "(아케인 도)", []string{"아케인셰이드 도"}},
+		{"Exclusion", "아케인 -스태프", "(아케인 -스태프)", []string{"아케인셰이드 투핸드소드", "아케인셰이드 검", "아케인셰이드 도"}},
+		{"Phrase", `"셰이드 투"`, `("셰이드 투")`, []string{"아케인셰이드 투핸드소드"}},
+		{"Phrase is not completed", `"셰이드 ㄷ"`, `("셰이드 ㄷ")`, nil},
+		{"Or", "검|도", "((검|도))", []string{"아케인셰이드 검", "아케인셰이드 도", "제네시스 도"}},
+		{"Or with spaces", "아케인 검 | 도", "(아케인 (검|도))", []string{"아케인셰이드 검", "아케인셰이드 도"}},
+		{"Excluded or", `-검|"도" 아케`, `(-(검|"도") 아케)`, []string{"아케인셰이드 스태프", "아케인셰이드 투핸드소드"}},
+		{"Last term completed / no match", "제네 돗", "(제네 돗)", nil},
+		{"Last term completed", "제넷", "(제넷)", []string{"제네시스 도"}},
+		{"Hyphen inside term", "MP-5", "(MP-5)", nil},
+		{"Empty", "", "()", targets},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := ParseQuery(tt.search)
+			if err != nil {
+				t.Fatalf("ParseQuery() error = %v", err)
+			}
+			if got := q.String(); got != tt.want {
+				t.Errorf("String() got = %v, want %v", got, tt.want)
+			}
+			if got := q.Filter(targets); !reflect.DeepEqual(got, tt.wantIn) {
+				t.Errorf("Filter() got = %v, want %v", got, tt.wantIn)
+			}
+		})
+	}
+}
+
+func TestParseQueryError(t *testing.T) {
+	tests := []struct {
+		search string
+		want   error
+	}{
+		{`"셰이드`, ErrUnterminatedQuote},
+		{`아케인 ""`, ErrEmptyQueryTerm},
+		{"검|", ErrEmptyQueryTerm},
+		{"|도", ErrEmptyQueryTerm},
+		{"검||도", ErrEmptyQueryTerm},
+		{"아케인 -", ErrEmptyQueryTerm},
+		{"아케인 - 스태프", ErrEmptyQueryTerm},
+	}
+	for _, tt := range tests {
+		t.Run(tt.search, func(t *testing.T) {
+			if _, err := ParseQuery(tt.search); !errors.Is(err, tt.want) {
+				t.Errorf("ParseQuery() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+	if _, err := ParseQuery("가", WithIgnoreSpace(), WithFuzzy()); !errors.Is(err, ErrIgnoreSpaceAndFuzzy) {
+		t.Errorf("ParseQuery() error = %v, want %v", err, ErrIgnoreSpaceAndFuzzy)
+	}
+}
+
+func TestParseQuery_Highlight(t *testing.T) {
+	q := MustParseQuery(`-스태프 검|도 "아케"`)
+	want := []TermHighlight{
+		{15, 18, []CharHighlight{{15, 18, []Span{{19, 22}}}}},
+		{20, 26, []CharHighlight{{20, 23, []Span{{0, 3}}}, {23, 26, []Span{{3, 6}}}}},
+	}
+	if got := q.Highlight("아케인셰이드 도"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Highlight() got = %v, want %v", got, want)
+	}
+}
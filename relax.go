@@ -0,0 +1,130 @@
+package hangul_regexp
+
+import "slices"
+
+// relaxSegments adds the syllables and jamo whose plain consonants are
+// replaced with their tense or aspirated counterparts to the atoms, as a
+// search typed without the shift key lacks them.
+func relaxSegments(segments []segment, tense, aspirated bool) {
+	for _, seg := range segments {
+		for _, alt := range seg.alts {
+			for i := range alt {
+				relaxAtom(&alt[i], tense, aspirated)
+			}
+		}
+	}
+}
+
+func relaxAtom(a *atom, tense, aspirated bool) {
+	lits := a.lits
+	add := func(ch rune) {
+		if !slices.Contains(lits, ch) {
+			lits = append(lits, ch)
+		}
+	}
+	for _, lit := range a.lits {
+		if IsHangul(lit) {
+			for _, ch := range relaxSyllable(lit, tense, aspirated) {
+				add(ch)
+			}
+		} else {
+			for _, ch := range relaxConsonant(lit, tense, aspirated) {
+				add(ch)
+			}
+		}
+	}
+	ranges := a.ranges
+	for _, r := range a.ranges {
+		if !IsHangul(r.lo) || !IsHangul(r.hi) {
+			continue
+		}
+		choOffset, jungOffset, _ := Disassemble(r.lo)
+		hiChoOffset, hiJungOffset, _ := Disassemble(r.hi)
+		if choOffset != hiChoOffset {
+			continue
+		}
+		// The syllables of a range share the choseong, so the range is moved
+		// to each relaxed one.
+		for _, cho := range relaxConsonant(choseongs[choOffset], tense, aspirated) {
+			shift := Assemble(GetChoseongOffset(cho), 0, 0) - Assemble(choOffset, 0, 0)
+			if shift != 0 {
+				ranges = append(ranges, runeRange{r.lo + shift, r.hi + shift})
+			}
+		}
+		// A range of a single jungseong only has some batchim, whose relaxed
+		// ones are added unless in the range.
+		if jungOffset == hiJungOffset {
+			for hangul := r.lo; hangul <= r.hi; hangul++ {
+				for _, ch := range relaxSyllable(hangul, tense, aspirated) {
+					if _, _, jongOffset := Disassemble(ch); !inRange(jongOffset, r) {
+						add(ch)
+					}
+				}
+			}
+		}
+	}
+	a.lits = lits
+	a.ranges = ranges
+}
+
+// inRange reports whether the syllable with the jongseong at jongOffset and
+// the choseong and jungseong of the range is in the range.
+func inRange(jongOffset int, r runeRange) bool {
+	choOffset, jungOffset, _ := Disassemble(r.lo)
+	ch := Assemble(choOffset, jungOffset, jongOffset)
+	return r.lo <= ch && ch <= r.hi
+}
+
+// relaxSyllable returns the syllables with the choseong and batchim of the
+// hangul or their relaxed counterparts, including the hangul.
+func relaxSyllable(hangul rune, tense, aspirated bool) []rune {
+	choOffset, jungOffset, jongOffset := Disassemble(hangul)
+	jongOffsets := []int{jongOffset}
+	if jongOffset > 0 {
+		for _, jong := range relaxConsonant(jongseongs[jongOffset], tense, aspirated)[1:] {
+			if offset := GetJongseongOffset(jong); offset > 0 {
+				jongOffsets = append(jongOffsets, offset)
+			}
+		}
+	}
+	var syllables []rune
+	for _, cho := range relaxConsonant(choseongs[choOffset], tense, aspirated) {
+		for _, offset := range jongOffsets {
+			syllables = append(syllables, Assemble(GetChoseongOffset(cho), jungOffset, offset))
+		}
+	}
+	return syllables
+}
+
+// relaxConsonant returns the consonant followed by its tense and aspirated
+// counterparts as enabled, or only the consonant if it has none.
+func relaxConsonant(ch rune, tense, aspirated bool) []rune {
+	relaxed := []rune{ch}
+	if tense {
+		switch ch {
+		case 'ㄱ':
+			relaxed = append(relaxed, 'ㄲ')
+		case 'ㄷ':
+			relaxed = append(relaxed, 'ㄸ')
+		case 'ㅂ':
+			relaxed = append(relaxed, 'ㅃ')
+		case 'ㅅ':
+			relaxed = append(relaxed, 'ㅆ')
+		case 'ㅈ':
+			relaxed = append(relaxed, 'ㅉ')
+		}
+	}
+	if aspirated {
+		switch ch {
+		case 'ㄱ':
+			relaxed = append(relaxed, 'ㅋ')
+		case 'ㄷ':
+			relaxed = append(relaxed, 'ㅌ')
+		case 'ㅂ':
+			relaxed = append(relaxed, 'ㅍ')
+		case 'ㅈ':
+			relaxed = append(relaxed, 'ㅊ')
+		}
+	}
+	return relaxed
+}
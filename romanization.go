@@ -0,0 +1,310 @@
+package hangul_regexp
+
+import (
+	"math/bits"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxRomanizedReadings limits the readings of a romanized word, as each one
+// becomes an alternative of the pattern. A search with a more ambiguous word
+// has no romanized branch.
+const maxRomanizedReadings = 1024
+
+// The first spelling of each jamo is its Revised Romanization. The others are
+// common variants, including McCune-Reischauer and spellings caused by
+// assimilation with the neighboring syllable.
+var choseongRomanizations = [...][]string{
+	{"g", "k"},
+	{"kk", "gg", "k"},
+	{"n"},
+	{"d", "t"},
+	{"tt", "dd", "t"},
+	{"r", "l", "n"},
+	{"m"},
+	{"b", "p"},
+	{"pp", "bb", "p"},
+	{"s", "sh"},
+	{"ss", "s"},
+	{""},
+	{"j", "ch"},
+	{"jj", "tch"},
+	{"ch", "c"},
+	{"k", "kh"},
+	{"t", "th"},
+	{"p", "ph", "f"},
+	{"h"},
+}
+
+var jungseongRomanizations = [...][]string{
+	{"a"},
+	{"ae"},
+	{"ya"},
+	{"yae"},
+	{"eo", "o"},
+	{"e"},
+	{"yeo", "yo"},
+	{"ye"},
+	{"o"},
+	{"wa"},
+	{"wae"},
+	{"oe", "we"},
+	{"yo"},
+	{"u", "oo"},
+	{"wo"},
+	{"we"},
+	{"wi"},
+	{"yu"},
+	{"eu", "u"},
+	{"ui", "eui"},
+	{"i", "ee"},
+}
+
+var jongseongRomanizations = [...][]string{
+	{""},
+	{"k", "g", "ng"},
+	{"k", "kk"},
+	{"k"},
+	{"n", "l"},
+	{"n"},
+	{"n"},
+	{"t", "d"},
+	{"l", "r"},
+	{"k", "l"},
+	{"m"},
+	{"l", "p"},
+	{"l"},
+	{"l"},
+	{"p"},
+	{"l"},
+	{"m"},
+	{"p", "b", "m"},
+	{"p"},
+	{"t", "s"},
+	{"t", "ss"},
+	{"ng"},
+	{"t", "j"},
+	{"t", "ch"},
+	{"k"},
+	{"t"},
+	{"p"},
+	{"t", "h"},
+}
+
+const syllableCount = len(choseongs) * len(jungseongs) * len(jongseongs)
+
+// syllableSet is a set of Hangul syllables, indexed by their offset from '가'.
+type syllableSet [(syllableCount + 63) / 64]uint64
+
+func (s *syllableSet) add(choOffset, jungOffset, jongOffset int) {
+	i := int(Assemble(choOffset, jungOffset, jongOffset) - '가')
+	s[i/64] |= 1 << (i % 64)
+}
+
+func (s *syllableSet) addAllJongseong(choOffset, jungOffset int) {
+	for jongOffset := range jongseongs {
+		s.add(choOffset, jungOffset, jongOffset)
+	}
+}
+
+func (s *syllableSet) addAllJungseong(choOffset int) {
+	for jungOffset := range jungseongs {
+		s.addAllJongseong(choOffset, jungOffset)
+	}
+}
+
+func (s *syllableSet) appendRanges(ranges []runeRange) []runeRange {
+	for w, word := range s {
+		for word != 0 {
+			i := bits.TrailingZeros64(word)
+			ch := '가' + rune(w*64+i)
+			if n := len(ranges); n > 0 && ranges[n-1].hi+1 == ch {
+				ranges[n-1].hi = ch
+			} else {
+				ranges = append(ranges, runeRange{ch, ch})
+			}
+			word &^= 1 << i
+		}
+	}
+	return ranges
+}
+
+// newRomanizedBranch returns a branch matching Hangul text whose romanization
+// matches the search. Each run of Latin letters is read as romanized
+// syllables, and the last syllable may be incomplete if complete is set. It
+// returns false if the search has no run, or a run has no reading or too many.
+func newRomanizedBranch(search string, complete bool) (branch, bool) {
+	b := newSegmentBuilder(len(search))
+	runStart := -1
+	read := false
+	for i, ch := range search {
+		if isLatinLetter(ch) {
+			if runStart < 0 {
+				runStart = i
+			}
+			continue
+		}
+		if runStart >= 0 {
+			if !b.addRomanized(search, runStart, i, false) {
+				return branch{}, false
+			}
+			runStart = -1
+			read = true
+		}
+		b.add(b.alt(b.literal(ch, i, i+utf8.RuneLen(ch))))
+	}
+	if runStart >= 0 {
+		if !b.addRomanized(search, runStart, len(search), complete) {
+			return branch{}, false
+		}
+		read = true
+	}
+	if !read {
+		return branch{}, false
+	}
+	return branch{query: search, segments: b.segments}, true
+}
+
+func isLatinLetter(ch rune) bool {
+	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z'
+}
+
+// addRomanized adds a segment with an alternative for each reading of the
+// letters between start and end. The last syllable may be incomplete if
+// partial is set. It returns false if the letters have no reading or too many.
+func (b *segmentBuilder) addRomanized(search string, start, end int, partial bool) bool {
+	r := romanizedReader{
+		b:       b,
+		text:    strings.ToLower(search[start:end]),
+		offset:  start,
+		partial: partial,
+		memo:    make(map[int][][]atom),
+	}
+	readings, ok := r.readings(0)
+	if !ok || len(readings) == 0 {
+		return false
+	}
+	alts := make([][]atom, len(readings))
+	for i, reading := range readings {
+		alts[i] = b.alt(reading...)
+	}
+	b.add(alts...)
+	return true
+}
+
+type romanizedReader struct {
+	b       *segmentBuilder
+	text    string
+	offset  int
+	partial bool
+	// memo holds the readings of the text from each offset.
+	memo map[int][][]atom
+}
+
+// readings returns the readings of the text from offset p, or false if there
+// are more than maxRomanizedReadings.
+func (r *romanizedReader) readings(p int) ([][]atom, bool) {
+	if p == len(r.text) {
+		return [][]atom{nil}, true
+	}
+	if readings, ok := r.memo[p]; ok {
+		return readings, true
+	}
+	var readings [][]atom
+	sets := r.syllables(p)
+	for q := p + 1; q <= len(r.text); q++ {
+		set, ok := sets[q]
+		if !ok {
+			continue
+		}
+		rest, ok := r.readings(q)
+		if !ok {
+			return nil, false
+		}
+		if len(rest) == 0 {
+			continue
+		}
+		a := atom{start: r.offset + p, end: r.offset + q, ranges: set.appendRanges(nil)}
+		for _, reading := range rest {
+			if len(readings) == maxRomanizedReadings {
+				return nil, false
+			}
+			readings = append(readings, append([]atom{a}, reading...))
+		}
+	}
+	r.memo[p] = readings
+	return readings, true
+}
+
+// syllables returns the syllables that can be read from offset p, by the
+// offset where each reading ends.
+func (r *romanizedReader) syllables(p int) map[int]*syllableSet {
+	sets := make(map[int]*syllableSet)
+	add := func(q int) *syllableSet {
+		set, ok := sets[q]
+		if !ok {
+			set = &syllableSet{}
+			sets[q] = set
+		}
+		return set
+	}
+	rest := r.text[p:]
+	for choOffset, choSpellings := range choseongRomanizations {
+		for _, c := range choSpellings {
+			if !strings.HasPrefix(rest, c) {
+				if r.partial && strings.HasPrefix(c, rest) {
+					add(len(r.text)).addAllJungseong(choOffset)
+				}
+				continue
+			}
+			afterCho := rest[len(c):]
+			if r.partial && afterCho == "" && c != "" {
+				add(len(r.text)).addAllJungseong(choOffset)
+			}
+			for jungOffset, jungSpellings := range jungseongRomanizations {
+				for _, v := range jungSpellings {
+					if !strings.HasPrefix(afterCho, v) {
+						if r.partial && afterCho != "" && strings.HasPrefix(v, afterCho) {
+							add(len(r.text)).addAllJongseong(choOffset, jungOffset)
+						}
+						continue
+					}
+					afterJung := afterCho[len(v):]
+					if r.partial && afterJung == "" {
+						add(len(r.text)).addAllJongseong(choOffset, jungOffset)
+						continue
+					}
+					r.addJongseong(add, afterJung, choOffset, jungOffset)
+					// An "r" after the vowel and before a consonant is silent,
+					// as in the English spelling "arkein" of 아케인.
+					if len(afterJung) > 1 && afterJung[0] == 'r' && !isRomanizedVowel(afterJung[1]) {
+						r.addJongseong(add, afterJung[1:], choOffset, jungOffset)
+					}
+				}
+			}
+		}
+	}
+	return sets
+}
+
+// addJongseong adds the syllables with the choseong and jungseong that end
+// with or without a jongseong read from afterJung.
+func (r *romanizedReader) addJongseong(add func(int) *syllableSet, afterJung string, choOffset, jungOffset int) {
+	jungEnd := len(r.text) - len(afterJung)
+	add(jungEnd).add(choOffset, jungOffset, 0)
+	for jongOffset := 1; jongOffset < len(jongseongs); jongOffset++ {
+		for _, f := range jongseongRomanizations[jongOffset] {
+			if strings.HasPrefix(afterJung, f) {
+				add(jungEnd+len(f)).add(choOffset, jungOffset, jongOffset)
+			} else if r.partial && strings.HasPrefix(f, afterJung) {
+				add(len(r.text)).add(choOffset, jungOffset, jongOffset)
+			}
+		}
+	}
+}
+
+// isRomanizedVowel reports whether the letter starts the spelling of a
+// jungseong.
+func isRomanizedVowel(ch byte) bool {
+	return strings.IndexByte("aeiouwy", ch) >= 0
+}
@@ -0,0 +1,96 @@
+package hangul_regexp
+
+import "testing"
+
+func TestRomanized(t *testing.T) {
+	tests := []struct {
+		search string
+		target string
+		want   bool
+	}{
+		{"gangnam", "강남역", true},
+		{"kangnam", "강남역", true},
+		{"gangnam", "강북", false},
+		{"arkein", "아케인셰이드", true},
+		{"arkeinsyeideu", "아케인셰이드", true},
+		{"arin", "아인", false},
+		{"arin", "아린", true},
+		{"akein", "아케인셰이드", true},
+		{"akeinsyeideu", "아케인셰이드", true},
+		{"akeinsyeide", "아케인셰이드", true},
+		{"akeinshyeideu", "아케인셰이드", true},
+		{"akeinsheideu", "아케인셰이드", false},
+		{"akeinseideu", "아케인세이드", true},
+		{"jongno", "종로3가", true},
+		{"silla", "신라", true},
+		{"seoul", "서울특별시", true},
+		{"soul", "서울", true},
+		{"busan", "부산", true},
+		{"pusan", "부산", true},
+		{"boosan", "부산", true},
+		{"hanguk", "한국어", true},
+		{"hangugeo", "한국어", true},
+		{"gan", "가나", true},
+		{"ga", "강", true},
+		{"gwa", "과일", true},
+		{"gw", "과일", true},
+		{"ch", "치즈", true},
+		{"kimchi 2", "김치 2", true},
+		{"kimchi 2", "김치 3", false},
+		{"gangnam", "gangnam style", true},
+		{"xyz", "가나다", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.search+" / "+tt.target, func(t *testing.T) {
+			m := MustNewMatcher(tt.search, WithRomanized())
+			if got := m.MatchString(tt.target); got != tt.want {
+				t.Errorf("MatchString() got = %v, want %v, pattern %v", got, tt.want, m)
+			}
+		})
+	}
+}
+
+func TestRomanizedPattern(t *testing.T) {
+	got, err := Pattern("ga", WithRomanized())
+	if err != nil {
+		t.Fatalf("Pattern() error = %v", err)
+	}
+	if want := "(?:ga|(?:[가-갷]|\u1100[\u1161-\u1162][\u11a8-\u11c2]?))"; got != want {
+		t.Errorf("Pattern() got = %v, want %v", got, want)
+	}
+}
+
+func TestRomanizedHighlight(t *testing.T) {
+	got, err := Highlight("gangnam", "강남역", WithRomanized())
+	if err != nil {
+		t.Fatalf("Highlight() error = %v", err)
+	}
+	want := []string{"강", "강", "강", "강", "남", "남", "남"}
+	for i, ch := range got {
+		if len(ch.Spans) != 1 || "강남역"[ch.Spans[0].Start:ch.Spans[0].End] != want[i] {
+			t.Errorf("Highlight() got = %v for %c, want %v", ch.Spans, "gangnam"[i], want[i])
+		}
+	}
+}
+
+func TestRomanizedPatternWithoutReading(t *testing.T) {
+	tests := []struct {
+		search string
+	}{
+		{"가나"},
+		{"xyz"},
+		{"eueueueueueueueueueueueueueueueu"},
+		{"ooooooooooooooooooooooooo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.search, func(t *testing.T) {
+			got, err := Pattern(tt.search, WithRomanized())
+			if err != nil {
+				t.Fatalf("Pattern() error = %v", err)
+			}
+			if want, _ := Pattern(tt.search); got != want {
+				t.Errorf("Pattern() got = %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package hangul_regexp
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Romanize converts Hangul into lower case Revised Romanization, applying the
+// sound changes between syllables of a word: 신라 becomes "silla" and 종로
+// becomes "jongno". Sound changes depending on the morphology, such as the
+// added ㄴ of 학여울 (Hangnyeoul), are not applied. Other characters are kept as
+// is.
+func Romanize(hangul string) string {
+	hangul, _ = normalizeJamo(hangul)
+	romanized, _ := romanize(hangul)
+	return romanized
+}
+
+// RomanizePattern returns a pattern matching the romanization of the search
+// case-insensitively, for searching romanized text with a Hangul search.
+func RomanizePattern(search string, opts ...Option) (string, error) {
+	o := NewOptions(opts...)
+	if err := o.Validate(); err != nil {
+		return "", err
+	}
+	search, _ = normalizeJamo(search)
+	if o.IgnoreSpace {
+		// The syllables are romanized across the spaces, so "신 라" is
+		// "silla".
+		search = strings.Join(strings.Fields(search), "")
+	}
+	romanized, sources := romanize(search)
+	br := newLatinBranch(romanized, sources)
+	if o.FoldCaseAndWidth {
+		foldSegments(br.segments)
+	}
+	w := newPatternWriter(o.connector(), o.Capturing)
+	w.writeAnchored([]branch{br}, o.Anchor)
+	return w.builder.String(), nil
+}
+
+// romanize returns the romanization of the hangul, and the byte offset in
+// hangul for each of its byte offsets and its length.
+func romanize(hangul string) (string, []int) {
+	builder := strings.Builder{}
+	builder.Grow(len(hangul))
+	sources := make([]int, 0, len(hangul)+1)
+	write := func(s string, source int) {
+		builder.WriteString(s)
+		for range len(s) {
+			sources = append(sources, source)
+		}
+	}
+
+	// initial is the spelling of the choseong of the current syllable, as
+	// decided at the boundary with the previous one.
+	initial := ""
+	prevHangul := false
+	for i, ch := range hangul {
+		if !IsHangul(ch) {
+			write(string(ch), i)
+			prevHangul = false
+			continue
+		}
+		choOffset, jungOffset, jongOffset := Disassemble(ch)
+		if !prevHangul {
+			initial = choseongRomanizations[choOffset][0]
+		}
+		write(initial, i)
+		write(jungseongRomanizations[jungOffset][0], i)
+
+		end := i + utf8.RuneLen(ch)
+		next, _ := utf8.DecodeRuneInString(hangul[end:])
+		if IsHangul(next) {
+			nextChoOffset, nextJungOffset, _ := Disassemble(next)
+			var final string
+			final, initial = romanizeBoundary(jongOffset, nextChoOffset, nextJungOffset)
+			write(final, i)
+		} else {
+			write(jongseongRomanizations[jongOffset][0], i)
+		}
+		prevHangul = true
+	}
+	sources = append(sources, len(hangul))
+	return builder.String(), sources
+}
+
+// romanizeBoundary returns the spellings of a jongseong and the following
+// choseong within a word.
+func romanizeBoundary(jongOffset, choOffset, jungOffset int) (string, string) {
+	jongseong := jongseongs[jongOffset]
+	choseong := choseongs[choOffset]
+	final := jongseongRomanizations[jongOffset][0]
+	initial := choseongRomanizations[choOffset][0]
+	if jongOffset == 0 {
+		return "", initial
+	}
+
+	switch choseong {
+	case 'ㅇ':
+		// The batchim moves over to the vowel of the next syllable.
+		if jongseong == 'ㅇ' {
+			return final, ""
+		}
+		first, moved := jongseong, jongseong
+		if !CanBeChoseong(jongseong) {
+			first, moved = SplitJongseong(jongseong)
+			if moved == 'ㅎ' {
+				// ㅎ is dropped before a vowel: 않아 becomes "ana".
+				moved = first
+				first = -1
+			}
+		} else {
+			first = -1
+		}
+		if jungseongs[jungOffset] == 'ㅣ' {
+			switch moved {
+			case 'ㄷ':
+				return romanizeFirstJongseong(first), "j"
+			case 'ㅌ':
+				return romanizeFirstJongseong(first), "ch"
+			}
+		}
+		if moved == 'ㅎ' {
+			return romanizeFirstJongseong(first), ""
+		}
+		return romanizeFirstJongseong(first), choseongRomanizations[GetChoseongOffset(moved)][0]
+	case 'ㅎ':
+		if jongseong == 'ㅎ' {
+			return "", initial
+		}
+		return final, initial
+	case 'ㄱ', 'ㄷ', 'ㅈ':
+		if jongseong == 'ㅎ' || jongseong == 'ㄶ' || jongseong == 'ㅀ' {
+			// The ㅎ aspirates the next consonant: 좋고 becomes "joko".
+			aspirated := choseongRomanizations[GetChoseongOffset(aspirate(choseong))][0]
+			if jongseong == 'ㅎ' {
+				return "", aspirated
+			}
+			return final, aspirated
+		}
+	case 'ㄴ', 'ㅁ':
+		switch final {
+		case "k":
+			return "ng", initial
+		case "t":
+			return "n", initial
+		case "p":
+			return "m", initial
+		case "l":
+			if choseong == 'ㄴ' {
+				return "l", "l"
+			}
+		}
+	case 'ㄹ':
+		switch final {
+		case "n", "l":
+			return "l", "l"
+		case "m", "ng":
+			return final, "n"
+		case "k":
+			return "ng", "n"
+		case "t":
+			return "n", "n"
+		case "p":
+			return "m", "n"
+		}
+	}
+	return final, initial
+}
+
+func romanizeFirstJongseong(jongseong rune) string {
+	if jongseong < 0 {
+		return ""
+	}
+	return jongseongRomanizations[GetJongseongOffset(jongseong)][0]
+}
+
+func aspirate(choseong rune) rune {
+	switch choseong {
+	case 'ㄱ':
+		return 'ㅋ'
+	case 'ㄷ':
+		return 'ㅌ'
+	case 'ㅈ':
+		return 'ㅊ'
+	}
+	return choseong
+}
@@ -0,0 +1,92 @@
+package hangul_regexp
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestRomanize(t *testing.T) {
+	// Examples from the Romanization of Korean by the National Institute of
+	// Korean Language.
+	tests := []struct {
+		hangul string
+		want   string
+	}{
+		{"구미", "gumi"},
+		{"영동", "yeongdong"},
+		{"백암", "baegam"},
+		{"옥천", "okcheon"},
+		{"합덕", "hapdeok"},
+		{"호법", "hobeop"},
+		{"월곶", "wolgot"},
+		{"벚꽃", "beotkkot"},
+		{"한밭", "hanbat"},
+		{"구리", "guri"},
+		{"설악", "seorak"},
+		{"칠곡", "chilgok"},
+		{"임실", "imsil"},
+		{"울릉", "ulleung"},
+		{"대관령", "daegwallyeong"},
+		{"백마", "baengma"},
+		{"종로", "jongno"},
+		{"왕십리", "wangsimni"},
+		{"별내", "byeollae"},
+		{"신라", "silla"},
+		{"해돋이", "haedoji"},
+		{"같이", "gachi"},
+		{"좋고", "joko"},
+		{"놓다", "nota"},
+		{"낳지", "nachi"},
+		{"묵호", "mukho"},
+		{"집현전", "jiphyeonjeon"},
+		{"압구정", "apgujeong"},
+		{"낙동강", "nakdonggang"},
+		{"죽변", "jukbyeon"},
+		{"낙성대", "nakseongdae"},
+		{"합정", "hapjeong"},
+		{"팔당", "paldang"},
+		{"샛별", "saetbyeol"},
+		{"울산", "ulsan"},
+		{"광희문", "gwanghuimun"},
+
+		{"", ""},
+		{"않아", "ana"},
+		{"읽어", "ilgeo"},
+		{"서울 2호선", "seoul 2hoseon"},
+		{"종 로", "jong ro"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.hangul, func(t *testing.T) {
+			if got := Romanize(tt.hangul); got != tt.want {
+				t.Errorf("Romanize() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRomanizePattern(t *testing.T) {
+	tests := []struct {
+		search string
+		opts   []Option
+		want   string
+		target string
+	}{
+		{"종로", nil, "[jJ][oO][nN][gG][nN][oO]", "Jongno-gu"},
+		{"신 라", []Option{WithIgnoreSpace()}, "[sS]" + spaceGap + "[iI]" + spaceGap + "[lL]" + spaceGap + "[lL]" + spaceGap + "[aA]", "SIL\u00a0LA"},
+		{"a1", []Option{WithCapturing()}, "([aA])(1)", "A1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.search, func(t *testing.T) {
+			got, err := RomanizePattern(tt.search, tt.opts...)
+			if err != nil {
+				t.Fatalf("RomanizePattern() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("RomanizePattern() got = %v, want %v", got, tt.want)
+			}
+			if !regexp.MustCompile(got).MatchString(tt.target) {
+				t.Errorf("RomanizePattern() %v does not match %v", got, tt.target)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package hangul_regexp
+
+import (
+	"slices"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Points of the relevance score, given for each matched rune of the target.
+const (
+	// scoreExact is given for a rune equal to the search character, and
+	// scorePartial for one only starting with it, such as a syllable matched
+	// by its choseong or by the unfinished last syllable.
+	scoreExact   = 4
+	scorePartial = 2
+	// scoreContiguous is given for a rune right after the previous one.
+	scoreContiguous = 3
+	scoreWordStart  = 2
+	// penaltyGap is taken for each rune skipped by the connector.
+	penaltyGap = 1
+)
+
+func Score(search, target string, opts ...Option) (int, bool, error) {
+	m, err := NewMatcher(search, opts...)
+	if err != nil {
+		return 0, false, err
+	}
+	score, ok := m.Score(target)
+	return score, ok, nil
+}
+
+func Rank(search string, targets []string, opts ...Option) ([]string, error) {
+	m, err := NewMatcher(search, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return m.Rank(targets), nil
+}
+
+// Score returns the relevance of the most relevant match in the target, out of
+// the leftmost-first matches starting at each position, or false if the
+// target does not match. A higher score is more relevant, and scores are only
+// comparable between targets of the same search.
+func (m *Matcher) Score(target string) (int, bool) {
+	target, _ = normalizeJamo(target)
+	native := m.native
+	if native == nil {
+		m.scoreOnce.Do(func() {
+			m.scoreNative = newNativeMatcher(buildBranches(m.search, m.opts, true), m.opts)
+		})
+		native = m.scoreNative
+	}
+	best, ok := 0, false
+	native.eachMatch(target, func(matches []matchedAtom) {
+		if s := score(m.search, target, matches); !ok || s > best {
+			best, ok = s, true
+		}
+	})
+	return best, ok
+}
+
+// Rank returns the matching targets from the most relevant. Targets of the
+// same score are ordered from the shortest, and then keep their order.
+func (m *Matcher) Rank(targets []string) []string {
+	type scored struct {
+		target string
+		score  int
+	}
+	var ranked []scored
+	for _, target := range targets {
+		if score, ok := m.Score(target); ok {
+			ranked = append(ranked, scored{target, score})
+		}
+	}
+	slices.SortStableFunc(ranked, func(a, b scored) int {
+		if a.score != b.score {
+			return b.score - a.score
+		}
+		return len(a.target) - len(b.target)
+	})
+	var result []string
+	for _, r := range ranked {
+		result = append(result, r.target)
+	}
+	return result
+}
+
+func score(search, target string, matches []matchedAtom) int {
+	score := 0
+	prevEnd := -1
+	for _, m := range matches {
+		matched := target[m.span.Start:m.span.End]
+		ch, _ := utf8.DecodeRuneInString(matched)
+		if matched == search[m.a.start:m.a.end] || slices.Contains(m.a.lits, ch) {
+			score += scoreExact
+		} else {
+			score += scorePartial
+		}
+		if m.span.Start == prevEnd {
+			score += scoreContiguous
+		} else if prevEnd >= 0 {
+			score -= penaltyGap * utf8.RuneCountInString(target[prevEnd:m.span.Start])
+		}
+		if isWordStart(target, m.span.Start) {
+			score += scoreWordStart
+		}
+		prevEnd = m.span.End
+	}
+	return score
+}
+
+// isWordStart reports whether the rune at byte offset i of s starts a word,
+// which is a run of letters and digits including Hangul.
+func isWordStart(s string, i int) bool {
+	ch, _ := utf8.DecodeRuneInString(s[i:])
+	if !isWordRune(ch) {
+		return false
+	}
+	prev, _ := utf8.DecodeLastRuneInString(s[:i])
+	return i == 0 || !isWordRune(prev)
+}
+
+func isWordRune(ch rune) bool {
+	return unicode.IsLetter(ch) || unicode.IsDigit(ch)
+}
@@ -0,0 +1,54 @@
+package hangul_regexp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name   string
+		search string
+		target string
+		opts   []Option
+		want   int
+		wantOk bool
+	}{
+		{"Exact word", "마깃", "마깃", nil, 4 + 2 + 4 + 3, true},
+		{"Partial last syllable", "마기", "마깃", nil, 4 + 2 + 2 + 3, true},
+		{"Choseong", "ㅁㄱ", "마깃", []Option{WithChoseong()}, 2 + 2 + 2 + 3, true},
+		{"Gap", "마깃", "마력이 깃든", []Option{WithFuzzy()}, 4 + 2 + 4 - 3 + 2, true},
+		{"Not word start", "깃", "마깃", nil, 4, true},
+		{"Best match", "마깃", "마력 깃 마깃", []Option{WithFuzzy()}, 4 + 2 + 4 + 3, true},
+		{"No match", "깃", "마력", nil, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, engine := range []Engine{EngineRegexp, EngineNative} {
+				got, ok, err := Score(tt.search, tt.target, append(tt.opts, WithEngine(engine))...)
+				if err != nil {
+					t.Fatalf("Score() error = %v", err)
+				}
+				if got != tt.want || ok != tt.wantOk {
+					t.Errorf("Score() got = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+				}
+			}
+		})
+	}
+}
+
+func TestRank(t *testing.T) {
+	targets := []string{"마법사의 깃털 안경 대여소", "블랙빈 마크", "마깃안 세트", "마력이 깃든 안대", "마력 깃 안", "마법사 마깃안"}
+	got, err := Rank("마깃안", targets, WithFuzzy())
+	if err != nil {
+		t.Fatalf("Rank() error = %v", err)
+	}
+	want := []string{"마깃안 세트", "마법사 마깃안", "마력 깃 안", "마력이 깃든 안대", "마법사의 깃털 안경 대여소"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Rank() got = %v, want %v", got, want)
+	}
+
+	if _, err := Rank("가", targets, WithIgnoreSpace(), WithFuzzy()); err == nil {
+		t.Errorf("Rank() error = nil, want error")
+	}
+}